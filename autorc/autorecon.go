@@ -91,6 +91,35 @@ func (c *Conn) SetMaxPktSize(new_size int) int {
 	return c.Raw.SetMaxPktSize(new_size)
 }
 
+func (c *Conn) SetConnAttr(name, value string) {
+	c.Raw.SetConnAttr(name, value)
+}
+
+func (c *Conn) SetAutoWarnings(on bool) {
+	c.Raw.SetAutoWarnings(on)
+}
+
+func (c *Conn) SetStrictWarnings(codes ...uint16) {
+	c.Raw.SetStrictWarnings(codes...)
+}
+
+func (c *Conn) SetTypedText(on bool) {
+	c.Raw.SetTypedText(on)
+}
+
+func (c *Conn) SetSharedRowBuffer(on bool) {
+	c.Raw.SetSharedRowBuffer(on)
+}
+
+// Sets the multi-statement option. If the connection was lost during the
+// command, the option is applied by reconnect.
+func (c *Conn) SetMultiStatements(on bool) (err error) {
+	err = c.Raw.SetMultiStatements(on)
+	nn := 0
+	c.reconnectIfNetErr(&nn, &err)
+	return
+}
+
 // Automatic connect/reconnect/repeat version of Use
 func (c *Conn) Use(dbname string) (err error) {
 	if err = c.connectIfNotConnected(); err != nil {
@@ -108,6 +137,35 @@ func (c *Conn) Use(dbname string) (err error) {
 	panic(nil)
 }
 
+// Automatic connect/reconnect/repeat version of ChangeUser
+func (c *Conn) ChangeUser(user, passwd, db string) (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if err = c.Raw.ChangeUser(user, passwd, db); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+// Automatic connect/reconnect version of ResetSession. If the connection
+// was lost, reconnect gives a clean session, so the command isn't repeated.
+func (c *Conn) ResetSession() (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	err = c.Raw.ResetSession()
+	nn := 0
+	c.reconnectIfNetErr(&nn, &err)
+	return
+}
+
 // Automatic connect/reconnect/repeat version of Query
 func (c *Conn) Query(sql string, params ...interface{}) (rows []mysql.Row, res mysql.Result, err error) {
 
@@ -160,6 +218,100 @@ func (c *Conn) QueryLast(sql string, params ...interface{}) (row mysql.Row, res
 	panic(nil)
 }
 
+// Automatic connect/reconnect/repeat version of Statistics
+func (c *Conn) Statistics() (st mysql.Statistics, err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if st, err = c.Raw.Statistics(); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+// Automatic connect/reconnect/repeat version of ProcessList
+func (c *Conn) ProcessList() (rows []mysql.Row, res mysql.Result, err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if rows, res, err = c.Raw.ProcessList(); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+// Automatic connect/reconnect/repeat version of Kill
+func (c *Conn) Kill(thr_id uint32) (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if err = c.Raw.Kill(thr_id); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+// Automatic connect/reconnect/repeat version of Refresh
+func (c *Conn) Refresh(flags byte) (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if err = c.Raw.Refresh(flags); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+// Automatic connect version of Shutdown. It never reconnects because the
+// server closes the connection during shutdown.
+func (c *Conn) Shutdown() (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	return c.Raw.Shutdown()
+}
+
+// Automatic connect/reconnect/repeat version of DumpDebugInfo
+func (c *Conn) DumpDebugInfo() (err error) {
+	if err = c.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if err = c.Raw.DumpDebugInfo(); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
 type Stmt struct {
 	Raw mysql.Stmt
 	con *Conn
@@ -196,6 +348,42 @@ func (c *Conn) Prepare(sql string) (*Stmt, error) {
 	return &s, nil
 }
 
+// Automatic connect/reconnect/repeat version of PrepareCached
+func (c *Conn) PrepareCached(sql string) (*Stmt, error) {
+	if err := c.connectIfNotConnected(); err != nil {
+		return nil, err
+	}
+	nn := 0
+	for {
+		raw, err := c.Raw.PrepareCached(sql)
+		if err == nil {
+			return &Stmt{Raw: raw, con: c}, nil
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return nil, err
+		}
+	}
+	panic(nil)
+}
+
+// Automatic reconnect/repeat version of SetStmtCacheSize
+func (c *Conn) SetStmtCacheSize(size int) (err error) {
+	nn := 0
+	for {
+		if err = c.Raw.SetStmtCacheSize(size); err == nil {
+			return
+		}
+		if c.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
+func (c *Conn) StmtCacheStats() mysql.StmtCacheStats {
+	return c.Raw.StmtCacheStats()
+}
+
 // Begin begins a transaction and calls f to complete it .
 // If f returns an error and IsNetErr(error) == true it reconnects and calls
 // f up to MaxRetries times. If error is of type *mysql.Error it tries rollback
@@ -227,6 +415,11 @@ func (s *Stmt) Bind(params ...interface{}) {
 	s.Raw.Bind(params...)
 }
 
+// See mysql.Stmt.BindNamed
+func (s *Stmt) BindNamed(params map[string]interface{}) error {
+	return s.Raw.BindNamed(params)
+}
+
 // Automatic connect/reconnect/repeat version of Exec
 func (s *Stmt) Exec(params ...interface{}) (rows []mysql.Row, res mysql.Result, err error) {
 
@@ -245,6 +438,24 @@ func (s *Stmt) Exec(params ...interface{}) (rows []mysql.Row, res mysql.Result,
 	panic(nil)
 }
 
+// Automatic connect/reconnect/repeat version of ExecCall
+func (s *Stmt) ExecCall(params ...interface{}) (cr *mysql.CallResult, err error) {
+
+	if err = s.con.connectIfNotConnected(); err != nil {
+		return
+	}
+	nn := 0
+	for {
+		if cr, err = s.Raw.ExecCall(params...); err == nil {
+			return
+		}
+		if s.con.reconnectIfNetErr(&nn, &err); err != nil {
+			return
+		}
+	}
+	panic(nil)
+}
+
 func (s *Stmt) ExecFirst(params ...interface{}) (row mysql.Row, res mysql.Result, err error) {
 
 	if err = s.con.connectIfNotConnected(); err != nil {
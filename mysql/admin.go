@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"strconv"
+	"time"
+)
+
+// Flags for Conn.Refresh. You can combine them using bitwise or.
+const (
+	REFRESH_GRANT   = 0x01 // Reload the grant tables
+	REFRESH_LOG     = 0x02 // Flush the logs
+	REFRESH_TABLES  = 0x04 // Flush the table cache
+	REFRESH_HOSTS   = 0x08 // Flush the host cache
+	REFRESH_STATUS  = 0x10 // Reset status variables
+	REFRESH_THREADS = 0x20 // Flush the thread cache
+	REFRESH_SLAVE   = 0x40 // Reset master info and restart slave thread
+	REFRESH_MASTER  = 0x80 // Remove binary logs and truncate the index file
+)
+
+// Server statistics returned by Conn.Statistics
+type Statistics struct {
+	Uptime           time.Duration
+	Threads          int
+	Questions        uint64
+	SlowQueries      uint64
+	Opens            uint64
+	FlushTables      uint64
+	OpenTables       int
+	QueriesPerSecAvg float64
+}
+
+// Parses statistics string returned by the server for COM_STATISTICS command,
+// e.g: "Uptime: 1234  Threads: 1  Questions: 56  Slow queries: 0 ...".
+// Unknown items are ignored.
+func ParseStatistics(str string) (st Statistics, err error) {
+	err = parseItems(str, func(k, v string) (err error) {
+		switch k {
+		case "Uptime":
+			var sec int64
+			sec, err = strconv.ParseInt(v, 10, 64)
+			st.Uptime = time.Duration(sec) * time.Second
+		case "Threads":
+			st.Threads, err = strconv.Atoi(v)
+		case "Questions":
+			st.Questions, err = strconv.ParseUint(v, 10, 64)
+		case "Slow queries":
+			st.SlowQueries, err = strconv.ParseUint(v, 10, 64)
+		case "Opens":
+			st.Opens, err = strconv.ParseUint(v, 10, 64)
+		case "Flush tables":
+			st.FlushTables, err = strconv.ParseUint(v, 10, 64)
+		case "Open tables":
+			st.OpenTables, err = strconv.Atoi(v)
+		case "Queries per second avg":
+			st.QueriesPerSecAvg, err = strconv.ParseFloat(v, 64)
+		}
+		return
+	})
+	return
+}
@@ -0,0 +1,32 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStatistics(t *testing.T) {
+	str := "Uptime: 3725  Threads: 2  Questions: 1234  Slow queries: 5  " +
+		"Opens: 33  Flush tables: 1  Open tables: 26  " +
+		"Queries per second avg: 0.331"
+	exp := Statistics{
+		Uptime:           3725 * time.Second,
+		Threads:          2,
+		Questions:        1234,
+		SlowQueries:      5,
+		Opens:            33,
+		FlushTables:      1,
+		OpenTables:       26,
+		QueriesPerSecAvg: 0.331,
+	}
+	st, err := ParseStatistics(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st != exp {
+		t.Fatalf("ParseStatistics: ret=%+v exp=%+v", st, exp)
+	}
+	if _, err = ParseStatistics("Uptime: x"); err == nil {
+		t.Fatal("ParseStatistics: expected error for bad value")
+	}
+}
@@ -0,0 +1,327 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	BULK_ROW_LEN_ERROR  = errors.New("mysql: wrong number of values in row")
+	BULK_ROW_SIZE_ERROR = errors.New(
+		"mysql: row doesn't fit in one packet (max_allowed_packet)",
+	)
+	BULK_REPLACE_ERROR = errors.New(
+		"mysql: REPLACE can't be used with IGNORE or ON DUPLICATE KEY UPDATE",
+	)
+)
+
+// Inserts many rows using multi-row INSERT statements:
+//
+//	INSERT INTO `table` (`col1`,`col2`) VALUES (...),(...),...
+//
+// Every statement is as long as possible but fits in one packet: its length
+// is limited by max_allowed_packet server variable and by the maximum packet
+// size of the connection (see Conn.SetMaxPktSize). Values are formatted as
+// SQL literals (strings are escaped using EscapeString, []byte values are
+// sent as hex literals).
+//
+// Example:
+//
+//	bi, err := mysql.NewBulkInserter(c, "orders", "id", "customer", "total")
+//	...
+//	for _, o := range orders {
+//		if err = bi.Add(o.Id, o.Customer, o.Total); err != nil {
+//			...
+//		}
+//	}
+//	err = bi.Flush()
+type BulkInserter struct {
+	c       ConnCommon
+	table   string
+	columns string // Quoted and separated by commas
+	ncol    int
+
+	ignore, replace bool
+	update          string
+
+	max_len int    // Maximum length of statement
+	stmt    []byte // Statement that is built
+	row     []byte // Last added row
+	rows    int    // Number of rows in stmt
+
+	affected  uint64
+	insert_id []uint64
+}
+
+// Returns a bulk inserter of rows into columns of table (table can be
+// qualified by database name: db.table). It queries the server for the value
+// of max_allowed_packet.
+func NewBulkInserter(c ConnCommon, table string, columns ...string) (*BulkInserter, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("mysql: no columns for bulk insert")
+	}
+	row, _, err := c.QueryFirst("SELECT @@max_allowed_packet")
+	if err != nil {
+		return nil, err
+	}
+	max_len, err := row.IntErr(0)
+	if err != nil {
+		return nil, err
+	}
+	if mc, ok := c.(interface{ SetMaxPktSize(int) int }); ok {
+		if n := mc.SetMaxPktSize(0); n > 0 && n < max_len {
+			max_len = n
+		}
+	}
+	// Statement is sent after command byte
+	bi := &BulkInserter{c: c, ncol: len(columns), max_len: max_len - 1}
+	for ii, name := range strings.Split(table, ".") {
+		if ii > 0 {
+			bi.table += "."
+		}
+		bi.table += quoteName(name)
+	}
+	for ii, name := range columns {
+		if ii > 0 {
+			bi.columns += ","
+		}
+		bi.columns += quoteName(name)
+	}
+	return bi, nil
+}
+
+func quoteName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Use INSERT IGNORE: rows that cause errors (eg. duplicate key) are skipped.
+// Returns BULK_REPLACE_ERROR if REPLACE is used.
+func (bi *BulkInserter) SetIgnore(on bool) error {
+	if on && bi.replace {
+		return BULK_REPLACE_ERROR
+	}
+	bi.ignore = on
+	return nil
+}
+
+// Use REPLACE instead of INSERT. Returns BULK_REPLACE_ERROR if IGNORE or
+// ON DUPLICATE KEY UPDATE is used.
+func (bi *BulkInserter) SetReplace(on bool) error {
+	if on && (bi.ignore || bi.update != "") {
+		return BULK_REPLACE_ERROR
+	}
+	bi.replace = on
+	return nil
+}
+
+// Appends ON DUPLICATE KEY UPDATE update to every statement (eg: update can be
+// "total=VALUES(total)"). Disabled if update is empty. Note that the server
+// counts every updated row as two affected rows. Returns BULK_REPLACE_ERROR
+// if REPLACE is used.
+func (bi *BulkInserter) SetOnDuplicate(update string) error {
+	if update != "" && bi.replace {
+		return BULK_REPLACE_ERROR
+	}
+	bi.update = update
+	return nil
+}
+
+// Returns the total number of rows affected by all executed statements.
+func (bi *BulkInserter) AffectedRows() uint64 {
+	return bi.affected
+}
+
+// Returns the insert ids returned by executed statements: one for every
+// statement, the id generated for the first row of the statement (0 if no
+// AUTO_INCREMENT value was generated).
+func (bi *BulkInserter) InsertIds() []uint64 {
+	return bi.insert_id
+}
+
+func (bi *BulkInserter) head() []byte {
+	stmt := bi.stmt[:0]
+	switch {
+	case bi.replace:
+		stmt = append(stmt, "REPLACE INTO "...)
+	case bi.ignore:
+		// IGNORE can be used together with ON DUPLICATE KEY UPDATE
+		stmt = append(stmt, "INSERT IGNORE INTO "...)
+	default:
+		stmt = append(stmt, "INSERT INTO "...)
+	}
+	stmt = append(stmt, bi.table...)
+	stmt = append(stmt, " ("...)
+	stmt = append(stmt, bi.columns...)
+	return append(stmt, ") VALUES "...)
+}
+
+func (bi *BulkInserter) tailLen() int {
+	if bi.update == "" {
+		return 0
+	}
+	return len(" ON DUPLICATE KEY UPDATE ") + len(bi.update)
+}
+
+// Adds a row. If the row doesn't fit in the current statement, the statement
+// is executed first.
+func (bi *BulkInserter) Add(row ...interface{}) error {
+	if len(row) != bi.ncol {
+		return BULK_ROW_LEN_ERROR
+	}
+	var err error
+	bi.row = append(bi.row[:0], '(')
+	for ii, v := range row {
+		if ii > 0 {
+			bi.row = append(bi.row, ',')
+		}
+		if bi.row, err = appendLiteral(bi.row, bi.c, v); err != nil {
+			return fmt.Errorf("mysql: value %d: %w", ii, err)
+		}
+	}
+	bi.row = append(bi.row, ')')
+
+	if bi.rows > 0 && len(bi.stmt)+1+len(bi.row)+bi.tailLen() > bi.max_len {
+		if err = bi.Flush(); err != nil {
+			return err
+		}
+	}
+	if bi.rows == 0 {
+		bi.stmt = bi.head()
+		if len(bi.stmt)+len(bi.row)+bi.tailLen() > bi.max_len {
+			return BULK_ROW_SIZE_ERROR
+		}
+	} else {
+		bi.stmt = append(bi.stmt, ',')
+	}
+	bi.stmt = append(bi.stmt, bi.row...)
+	bi.rows++
+	return nil
+}
+
+// Executes the statement with rows added after the last Flush (if any).
+func (bi *BulkInserter) Flush() error {
+	if bi.rows == 0 {
+		return nil
+	}
+	if bi.update != "" {
+		bi.stmt = append(bi.stmt, " ON DUPLICATE KEY UPDATE "...)
+		bi.stmt = append(bi.stmt, bi.update...)
+	}
+	// Rows are discarded even if the statement fails
+	bi.rows = 0
+	_, res, err := bi.c.Query(string(bi.stmt))
+	if err != nil {
+		return err
+	}
+	bi.affected += res.AffectedRows()
+	bi.insert_id = append(bi.insert_id, res.InsertId())
+	return nil
+}
+
+// Adds all rows and flushes the inserter.
+func (bi *BulkInserter) Insert(rows ...[]interface{}) error {
+	for _, row := range rows {
+		if err := bi.Add(row...); err != nil {
+			return err
+		}
+	}
+	return bi.Flush()
+}
+
+// Appends v to buf as SQL literal. Values are converted like parameters of
+// prepared statements: registered encoders and driver.Valuer are used.
+func appendLiteral(buf []byte, c ConnCommon, v interface{}) ([]byte, error) {
+	if v == nil {
+		return append(buf, "NULL"...), nil
+	}
+	if enc := GetEncoder(reflect.TypeOf(v)); enc != nil {
+		ev, err := enc(v)
+		if err != nil {
+			return buf, err
+		}
+		return appendLiteral(buf, c, ev)
+	}
+	switch x := v.(type) {
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return append(buf, "NULL"...), nil
+		}
+		dv, err := x.Value()
+		if err != nil {
+			return buf, err
+		}
+		return appendLiteral(buf, c, dv)
+	case string:
+		return appendString(buf, c, x), nil
+	case []byte:
+		return appendBytes(buf, x), nil
+	case Blob:
+		return appendBytes(buf, x), nil
+	case time.Time:
+		return appendString(buf, c, TimeString(x)), nil
+	case Timestamp:
+		return appendString(buf, c, TimeString(x.Time)), nil
+	case Date:
+		return appendString(buf, c, x.String()), nil
+	case time.Duration:
+		return appendString(buf, c, DurationString(x)), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return append(buf, "NULL"...), nil
+		}
+		return appendLiteral(buf, c, rv.Elem().Interface())
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(buf, "TRUE"...), nil
+		}
+		return append(buf, "FALSE"...), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return strconv.AppendInt(buf, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(buf, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return buf, errors.New("NaN or infinity can't be inserted")
+		}
+		bits := 64
+		if rv.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		return strconv.AppendFloat(buf, f, 'g', -1, bits), nil
+	case reflect.String:
+		return appendString(buf, c, rv.String()), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return appendBytes(buf, rv.Bytes()), nil
+		}
+	}
+	return buf, fmt.Errorf("unsupported type %T", v)
+}
+
+func appendString(buf []byte, c ConnCommon, s string) []byte {
+	buf = append(buf, '\'')
+	buf = append(buf, c.EscapeString(s)...)
+	return append(buf, '\'')
+}
+
+func appendBytes(buf []byte, b []byte) []byte {
+	if len(b) == 0 {
+		return append(buf, "''"...)
+	}
+	buf = append(buf, "X'"...)
+	buf = hex.AppendEncode(buf, b)
+	return append(buf, '\'')
+}
@@ -0,0 +1,162 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bulkResult struct {
+	Result
+	affected, insert_id uint64
+}
+
+func (r *bulkResult) AffectedRows() uint64 { return r.affected }
+func (r *bulkResult) InsertId() uint64     { return r.insert_id }
+
+// Connection that records queries
+type bulkConn struct {
+	ConnCommon
+	max_allowed, max_pkt int
+	queries              []string
+}
+
+func (c *bulkConn) QueryFirst(sql string, params ...interface{}) (Row, Result, error) {
+	return Row{[]byte(itoa(c.max_allowed))}, nil, nil
+}
+
+func (c *bulkConn) Query(sql string, params ...interface{}) ([]Row, Result, error) {
+	c.queries = append(c.queries, sql)
+	n := uint64(strings.Count(sql, "),(") + 1)
+	return nil, &bulkResult{affected: n, insert_id: 100 * uint64(len(c.queries))}, nil
+}
+
+func (c *bulkConn) EscapeString(txt string) string {
+	return strings.ReplaceAll(txt, "'", `\'`)
+}
+
+func (c *bulkConn) SetMaxPktSize(new_size int) int {
+	return c.max_pkt
+}
+
+func itoa(i int) string {
+	return string(Row{int64(i)}.Bin(0))
+}
+
+func TestBulkInsert(t *testing.T) {
+	c := &bulkConn{max_allowed: 1 << 20, max_pkt: 65}
+	bi, err := NewBulkInserter(c, "db.t", "a", "b`c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bi.SetIgnore(true); err != nil {
+		t.Fatal(err)
+	}
+	// Statement head is 48 bytes long, every row is 7 bytes long, limit is
+	// 64 bytes: two rows fit in one statement.
+	for ii := 0; ii < 5; ii++ {
+		if err = bi.Add(ii, "x"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = bi.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	exp := []string{
+		"INSERT IGNORE INTO `db`.`t` (`a`,`b``c`) VALUES (0,'x'),(1,'x')",
+		"INSERT IGNORE INTO `db`.`t` (`a`,`b``c`) VALUES (2,'x'),(3,'x')",
+		"INSERT IGNORE INTO `db`.`t` (`a`,`b``c`) VALUES (4,'x')",
+	}
+	if strings.Join(c.queries, "\n") != strings.Join(exp, "\n") {
+		t.Fatalf("Bad queries:\n%s", strings.Join(c.queries, "\n"))
+	}
+	for _, q := range c.queries {
+		if len(q) > 64 {
+			t.Fatalf("Query too long (%d): %s", len(q), q)
+		}
+	}
+	ids := bi.InsertIds()
+	if bi.AffectedRows() != 5 || len(ids) != 3 || ids[2] != 300 {
+		t.Fatalf("Bad result: %d %v", bi.AffectedRows(), ids)
+	}
+	if err = bi.Add(1); err != BULK_ROW_LEN_ERROR {
+		t.Fatal("Expected BULK_ROW_LEN_ERROR, got:", err)
+	}
+	if err = bi.Add(1, strings.Repeat("y", 20)); err != BULK_ROW_SIZE_ERROR {
+		t.Fatal("Expected BULK_ROW_SIZE_ERROR, got:", err)
+	}
+}
+
+func TestBulkInsertValues(t *testing.T) {
+	c := &bulkConn{max_allowed: 1 << 20}
+	bi, err := NewBulkInserter(c, "t", "a", "b", "c", "d", "e", "f", "g", "h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bi.SetOnDuplicate("a=VALUES(a)"); err != nil {
+		t.Fatal(err)
+	}
+	var nilp *int
+	err = bi.Insert(
+		[]interface{}{
+			nil, true, int8(-3), uint64(1 << 63), 1.5, "it's",
+			[]byte{0xde, 0xad}, sql.NullInt64{},
+		},
+		[]interface{}{
+			nilp, Date{2013, 7, 14}, time.Date(2013, 7, 14, 15, 4, 5, 0,
+				time.UTC), Blob{}, Set{"a", "b"}, -time.Hour, float32(0.1),
+			Bit(5),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "INSERT INTO `t` (`a`,`b`,`c`,`d`,`e`,`f`,`g`,`h`) VALUES " +
+		`(NULL,TRUE,-3,9223372036854775808,1.5,'it\'s',X'dead',NULL),` +
+		`(NULL,'2013-07-14','2013-07-14 15:04:05','','a,b','-1:00:00',0.1,5)` +
+		" ON DUPLICATE KEY UPDATE a=VALUES(a)"
+	if len(c.queries) != 1 || c.queries[0] != exp {
+		t.Fatalf("Bad query:\n%s\n%s", c.queries, exp)
+	}
+	if err = bi.Add(struct{}{}, 1, 2, 3, 4, 5, 6, 7); err == nil {
+		t.Fatal("No error for unsupported type")
+	}
+}
+
+func TestBulkInsertReplace(t *testing.T) {
+	c := &bulkConn{max_allowed: 1 << 20}
+	bi, err := NewBulkInserter(c, "t", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = bi.SetReplace(true); err != nil {
+		t.Fatal(err)
+	}
+	if err = bi.SetIgnore(true); err != BULK_REPLACE_ERROR {
+		t.Fatal("Expected BULK_REPLACE_ERROR, got:", err)
+	}
+	if err = bi.SetOnDuplicate("a=1"); err != BULK_REPLACE_ERROR {
+		t.Fatal("Expected BULK_REPLACE_ERROR, got:", err)
+	}
+	if err = bi.Insert([]interface{}{1}, []interface{}{2}); err != nil {
+		t.Fatal(err)
+	}
+	exp := "REPLACE INTO `t` (`a`) VALUES (1),(2)"
+	if len(c.queries) != 1 || c.queries[0] != exp {
+		t.Fatalf("Bad query: %s", c.queries)
+	}
+
+	// REPLACE can be enabled after disabling incompatible options
+	bi, _ = NewBulkInserter(c, "t", "a")
+	bi.SetIgnore(true)
+	bi.SetOnDuplicate("a=1")
+	if err = bi.SetReplace(true); err != BULK_REPLACE_ERROR {
+		t.Fatal("Expected BULK_REPLACE_ERROR, got:", err)
+	}
+	bi.SetIgnore(false)
+	bi.SetOnDuplicate("")
+	if err = bi.SetReplace(true); err != nil {
+		t.Fatal(err)
+	}
+}
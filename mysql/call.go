@@ -0,0 +1,61 @@
+package mysql
+
+// Result set with all its rows
+type ResultSet struct {
+	Result Result // Use it to obtain fields or map column names
+	Rows   []Row
+}
+
+// Result of the stored procedure call (see ExecCall)
+type CallResult struct {
+	Sets []ResultSet // Result sets produced by statements in procedure
+
+	// Values of OUT and INOUT parameters (nil if procedure has no such
+	// parameters) and the result that contains them (OutRes.Map returns
+	// index of parameter with given name).
+	Out    Row
+	OutRes Result
+
+	Status Result // The final status result of the call
+}
+
+// Calls Run for prepared CALL statement and reads all results. Result sets
+// produced by the procedure are returned separately from the values of OUT and
+// INOUT parameters.
+//
+// Example:
+//
+//	// CREATE PROCEDURE p(IN n INT, OUT total INT) ...
+//	stmt, err := c.Prepare("CALL p(?, ?)")
+//	...
+//	cr, err := stmt.ExecCall(10, nil)
+//	...
+//	total := cr.Out.Int(cr.OutRes.Map("total"))
+func ExecCall(s Stmt, params ...interface{}) (*CallResult, error) {
+	res, err := s.Run(params...)
+	if err != nil {
+		return nil, err
+	}
+	cr := new(CallResult)
+	for res != nil {
+		rows, err := GetRows(res)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case res.IsOutParams():
+			if len(rows) > 0 {
+				cr.Out = rows[0]
+			}
+			cr.OutRes = res
+		case res.StatusOnly():
+			cr.Status = res
+		default:
+			cr.Sets = append(cr.Sets, ResultSet{res, rows})
+		}
+		if res, err = res.NextResult(); err != nil {
+			return nil, err
+		}
+	}
+	return cr, nil
+}
@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+type callResult struct {
+	memResult
+	out  bool
+	next *callResult
+}
+
+func (r *callResult) IsOutParams() bool    { return r.out }
+func (r *callResult) MoreResults() bool    { return r.next != nil }
+func (r *callResult) GetRow() (Row, error) { return GetRow(r) }
+
+func (r *callResult) NextResult() (Result, error) {
+	if r.next == nil {
+		return nil, nil
+	}
+	return r.next, nil
+}
+
+type callStmt struct {
+	Stmt
+	res *callResult
+}
+
+func (s callStmt) Run(params ...interface{}) (Result, error) {
+	return s.res, nil
+}
+
+func TestExecCall(t *testing.T) {
+	status := &callResult{}
+	out := &callResult{
+		memResult: memResult{fields: scanFields("total"), rows: []Row{{10}}},
+		out:       true,
+		next:      status,
+	}
+	set := &callResult{
+		memResult: memResult{fields: scanFields("a"), rows: []Row{{1}, {2}}},
+		next:      out,
+	}
+	cr, err := ExecCall(callStmt{res: set})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cr.Sets) != 1 || cr.Sets[0].Result != set ||
+		!reflect.DeepEqual(cr.Sets[0].Rows, []Row{{1}, {2}}) {
+		t.Fatalf("Bad result sets: %+v", cr.Sets)
+	}
+	if !reflect.DeepEqual(cr.Out, Row{10}) || cr.OutRes != out {
+		t.Fatalf("Bad OUT parameters: %v", cr.Out)
+	}
+	if cr.Status != status {
+		t.Fatal("Bad status result")
+	}
+}
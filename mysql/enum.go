@@ -0,0 +1,202 @@
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value of BIT(M) column. The server sends BIT values as big-endian binary
+// strings, use DecodeBit or Row.Bit to convert them. Bit can be binded as
+// statement parameter (it is sent as unsigned BIGINT) and String method
+// returns it as bit-value literal, so it can be used in text queries.
+type Bit uint64
+
+// Decodes BIT value received from the server.
+func DecodeBit(b []byte) (Bit, error) {
+	if len(b) > 8 {
+		return 0, errors.New("mysql: BIT value longer than 64 bits")
+	}
+	var v Bit
+	for _, c := range b {
+		v = v<<8 | Bit(c)
+	}
+	return v, nil
+}
+
+// Returns b as bit-value literal (eg. b'101').
+func (b Bit) String() string {
+	return "b'" + strconv.FormatUint(uint64(b), 2) + "'"
+}
+
+// Implements sql.Scanner interface.
+func (b *Bit) Scan(src interface{}) (err error) {
+	switch v := src.(type) {
+	case nil:
+		*b = 0
+	case []byte:
+		*b, err = DecodeBit(v)
+	case string:
+		*b, err = DecodeBit([]byte(v))
+	case int64:
+		*b = Bit(v)
+	default:
+		err = fmt.Errorf("mysql: can't scan %T into Bit", src)
+	}
+	return
+}
+
+// Value of SET column: its members. Set can be binded as statement
+// parameter and String method returns it in the form used by MySQL.
+type Set []string
+
+// Returns members of s separated by commas.
+func (s Set) String() string {
+	return strings.Join(s, ",")
+}
+
+// Implements driver.Valuer interface. Returns an error if any member
+// contains comma.
+func (s Set) Value() (driver.Value, error) {
+	for _, m := range s {
+		if strings.IndexByte(m, ',') != -1 {
+			return nil, fmt.Errorf("mysql: SET member %q contains comma", m)
+		}
+	}
+	return s.String(), nil
+}
+
+// Implements sql.Scanner interface.
+func (s *Set) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*s = nil
+	case []byte:
+		*s = splitSet(string(v))
+	case string:
+		*s = splitSet(v)
+	default:
+		return fmt.Errorf("mysql: can't scan %T into Set", src)
+	}
+	return nil
+}
+
+func splitSet(str string) []string {
+	if str == "" {
+		return []string{}
+	}
+	return strings.Split(str, ",")
+}
+
+// Allowed values of ENUM or SET column in order of definition (see
+// LoadEnumValues).
+type EnumValues []string
+
+// Parses column type of ENUM or SET column, eg: enum('a','b').
+func ParseEnumValues(column_type string) (EnumValues, error) {
+	str := column_type
+	if n := strings.IndexByte(str, '('); n != -1 {
+		switch strings.ToLower(str[:n]) {
+		case "enum", "set":
+			str = str[n+1:]
+		}
+	}
+	if str == column_type || !strings.HasSuffix(str, ")") {
+		return nil, errors.New("mysql: not ENUM/SET column type: " + column_type)
+	}
+	str = str[:len(str)-1]
+	var ev EnumValues
+	for len(str) != 0 {
+		if str[0] != '\'' {
+			goto invalid
+		}
+		var val []byte
+		ii := 1
+		for ; ii < len(str); ii++ {
+			c := str[ii]
+			if c == '\\' && ii+1 < len(str) {
+				ii++
+				c = str[ii]
+			} else if c == '\'' {
+				if ii+1 < len(str) && str[ii+1] == '\'' {
+					// Doubled quote
+					ii++
+				} else {
+					break
+				}
+			}
+			val = append(val, c)
+		}
+		if ii == len(str) {
+			goto invalid
+		}
+		ev = append(ev, string(val))
+		str = str[ii+1:]
+		if len(str) != 0 {
+			if str[0] != ',' {
+				goto invalid
+			}
+			str = str[1:]
+		}
+	}
+	return ev, nil
+
+invalid:
+	return nil, errors.New("mysql: invalid ENUM/SET column type: " + column_type)
+}
+
+// Fetches allowed values of ENUM or SET column from information_schema.
+// table can be qualified by database name (db.table), otherwise the current
+// database is used.
+func LoadEnumValues(c ConnCommon, table, column string) (EnumValues, error) {
+	schema := "DATABASE()"
+	if n := strings.IndexByte(table, '.'); n != -1 {
+		schema = "'" + c.EscapeString(table[:n]) + "'"
+		table = table[n+1:]
+	}
+	row, _, err := c.QueryFirst(
+		"SELECT column_type FROM information_schema.columns"+
+			" WHERE table_schema=%s AND table_name='%s' AND column_name='%s'",
+		schema, c.EscapeString(table), c.EscapeString(column),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, fmt.Errorf("mysql: column %s.%s doesn't exist", table,
+			column)
+	}
+	return ParseEnumValues(row.Str(0))
+}
+
+// Returns the index of v (1 for the first allowed value, as numbered by
+// MySQL) or 0 if v isn't allowed. Values are compared case-insensitively.
+func (ev EnumValues) Index(v string) int {
+	for ii, a := range ev {
+		if strings.EqualFold(a, v) {
+			return ii + 1
+		}
+	}
+	return 0
+}
+
+// Returns an error if v isn't allowed value.
+func (ev EnumValues) Check(v string) error {
+	if ev.Index(v) == 0 {
+		return fmt.Errorf("mysql: %q isn't allowed value (%s)", v,
+			strings.Join(ev, ","))
+	}
+	return nil
+}
+
+// Returns an error if any member of s isn't allowed value.
+func (ev EnumValues) CheckSet(s []string) error {
+	for _, m := range s {
+		if err := ev.Check(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
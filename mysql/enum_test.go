@@ -0,0 +1,97 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBit(t *testing.T) {
+	b, err := DecodeBit([]byte{0x01, 0x05})
+	if err != nil || b != 0x105 {
+		t.Fatalf("DecodeBit: %x %v", b, err)
+	}
+	if s := Bit(5).String(); s != "b'101'" {
+		t.Fatal("Bad String:", s)
+	}
+	if _, err = DecodeBit(make([]byte, 9)); err == nil {
+		t.Fatal("No error for 9 bytes")
+	}
+	row := Row{[]byte{0xff}, nil, uint64(3), Bit(7)}
+	for ii, v := range []Bit{0xff, 0, 3, 7} {
+		if b := row.Bit(ii); b != v {
+			t.Fatalf("Row.Bit(%d): %v != %v", ii, b, v)
+		}
+	}
+	if err = b.Scan([]byte{0x80, 0, 0, 0, 0, 0, 0, 0}); err != nil ||
+		b != 1<<63 {
+		t.Fatalf("Scan: %x %v", b, err)
+	}
+}
+
+func TestYear(t *testing.T) {
+	row := Row{int16(2013), uint16(1901), []byte("2155"), []byte("0000"), nil}
+	for ii, v := range []int{2013, 1901, 2155, 0, 0} {
+		if y := row.Year(ii); y != v {
+			t.Fatalf("Row.Year(%d): %d != %d", ii, y, v)
+		}
+	}
+	row = Row{int16(1900), []byte("2156"), []byte("13a")}
+	for ii := range row {
+		if _, err := row.YearErr(ii); err == nil {
+			t.Fatalf("Row.YearErr(%d): no error", ii)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	row := Row{[]byte("a,b"), []byte{}, nil}
+	if s := row.Set(0); !reflect.DeepEqual(s, []string{"a", "b"}) {
+		t.Fatal("Bad set:", s)
+	}
+	if s := row.Set(1); s == nil || len(s) != 0 {
+		t.Fatal("Bad empty set:", s)
+	}
+	if s := row.Set(2); s != nil {
+		t.Fatal("Bad NULL set:", s)
+	}
+	if v, err := (Set{"a", "b"}).Value(); err != nil || v != "a,b" {
+		t.Fatalf("Value: %v %v", v, err)
+	}
+	if _, err := (Set{"a,b"}).Value(); err == nil {
+		t.Fatal("No error for member with comma")
+	}
+	var s Set
+	if err := s.Scan([]byte("x,y")); err != nil ||
+		!reflect.DeepEqual(s, Set{"x", "y"}) {
+		t.Fatalf("Scan: %v %v", s, err)
+	}
+}
+
+func TestParseEnumValues(t *testing.T) {
+	ev, err := ParseEnumValues(`enum('a','it''s','b\\c','')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ev, EnumValues{"a", "it's", `b\c`, ""}) {
+		t.Fatalf("Bad values: %q", ev)
+	}
+	if ev.Index("A") != 1 || ev.Index("it's") != 2 || ev.Index("x") != 0 {
+		t.Fatal("Bad Index")
+	}
+	if ev.Check("a") != nil || ev.Check("x") == nil {
+		t.Fatal("Bad Check")
+	}
+	if ev.CheckSet([]string{"a", "it's"}) != nil ||
+		ev.CheckSet([]string{"a", "x"}) == nil {
+		t.Fatal("Bad CheckSet")
+	}
+	if ev, err = ParseEnumValues("set('x,y')"); err != nil ||
+		!reflect.DeepEqual(ev, EnumValues{"x,y"}) {
+		t.Fatalf("Bad set values: %q %v", ev, err)
+	}
+	for _, s := range []string{"int(11)", "enum('a'", "enum('a' 'b')", "enum(a)"} {
+		if _, err = ParseEnumValues(s); err == nil {
+			t.Fatalf("No error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package mysql
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+)
+
+// Returns MySQL server error contained in err or nil if err isn't (or doesn't
+// wrap) a *Error or Error.
+func AsError(err error) *Error {
+	var pe *Error
+	if errors.As(err, &pe) {
+		return pe
+	}
+	var e Error
+	if errors.As(err, &e) {
+		return &e
+	}
+	return nil
+}
+
+func hasCode(err error, codes ...uint16) bool {
+	if e := AsError(err); e != nil {
+		for _, c := range codes {
+			if e.Code == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns true if err is a duplicate key/entry error.
+func IsDuplicateKey(err error) bool {
+	return hasCode(err, ER_DUP_ENTRY, ER_DUP_KEY, ER_DUP_UNIQUE,
+		ER_DUP_ENTRY_WITH_KEY_NAME)
+}
+
+// Returns true if the transaction was rolled back because of deadlock.
+func IsDeadlock(err error) bool {
+	return hasCode(err, ER_LOCK_DEADLOCK)
+}
+
+// Returns true if lock wait timeout was exceeded.
+func IsLockWaitTimeout(err error) bool {
+	return hasCode(err, ER_LOCK_WAIT_TIMEOUT)
+}
+
+// Returns true if the statement was rejected because the server or the
+// transaction is in read only mode.
+func IsReadOnly(err error) bool {
+	if hasCode(err, ER_READ_ONLY_MODE, ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION,
+		ER_READ_ONLY_TRANSACTION) {
+		return true
+	}
+	e := AsError(err)
+	return e != nil && e.Code == ER_OPTION_PREVENTS_STATEMENT &&
+		strings.Contains(string(e.Msg), "read-only")
+}
+
+// Returns true if err means that the connection to the server was lost:
+// network error, unexpected EOF, connection exception SQLSTATE (class 08) or
+// server shutdown.
+func IsConnectionLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+	if e := AsError(err); e != nil {
+		if strings.HasPrefix(e.SqlState, "08") {
+			return true
+		}
+		switch e.Code {
+		case ER_SERVER_SHUTDOWN, ER_NET_READ_ERROR, ER_NET_READ_INTERRUPTED,
+			ER_NET_ERROR_ON_WRITE, ER_NET_WRITE_INTERRUPTED:
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the failed command (or the whole transaction) may succeed
+// if you repeat it: deadlock, lock wait timeout, serialization failure
+// (SQLSTATE 40001) or lost connection.
+func IsRetryable(err error) bool {
+	if IsDeadlock(err) || IsLockWaitTimeout(err) || IsConnectionLost(err) {
+		return true
+	}
+	e := AsError(err)
+	return e != nil && e.SqlState == "40001"
+}
@@ -0,0 +1,57 @@
+package mysql
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+type wrapErr struct {
+	error
+}
+
+func (w wrapErr) Unwrap() error {
+	return w.error
+}
+
+func TestErrorString(t *testing.T) {
+	err := &Error{Code: ER_DUP_ENTRY, SqlState: "23000", Msg: []byte("dup")}
+	exp := `Received ER_DUP_ENTRY (1062, 23000) error from MySQL server: "dup"`
+	if err.Error() != exp {
+		t.Fatalf("Error: ret='%s' exp='%s'", err.Error(), exp)
+	}
+	err = &Error{Code: 9999, SqlState: "HY000", Msg: []byte("?")}
+	exp = `Received #9999 (HY000) error from MySQL server: "?"`
+	if err.Error() != exp {
+		t.Fatalf("Error: ret='%s' exp='%s'", err.Error(), exp)
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	dup := &Error{Code: ER_DUP_ENTRY, SqlState: "23000"}
+	dl := &Error{Code: ER_LOCK_DEADLOCK, SqlState: "40001"}
+	lw := &Error{Code: ER_LOCK_WAIT_TIMEOUT, SqlState: "HY000"}
+	ro := &Error{Code: ER_OPTION_PREVENTS_STATEMENT, SqlState: "HY000",
+		Msg: []byte("The MySQL server is running with the --read-only option")}
+	wrapped := wrapErr{dup}
+
+	if !IsDuplicateKey(dup) || !IsDuplicateKey(wrapped) || IsDuplicateKey(dl) {
+		t.Error("IsDuplicateKey")
+	}
+	if !IsDeadlock(dl) || IsDeadlock(lw) {
+		t.Error("IsDeadlock")
+	}
+	if !IsLockWaitTimeout(lw) || IsLockWaitTimeout(dl) {
+		t.Error("IsLockWaitTimeout")
+	}
+	if !IsReadOnly(ro) || IsReadOnly(dup) {
+		t.Error("IsReadOnly")
+	}
+	if !IsConnectionLost(io.ErrUnexpectedEOF) || IsConnectionLost(dup) ||
+		IsConnectionLost(errors.New("x")) || IsConnectionLost(nil) {
+		t.Error("IsConnectionLost")
+	}
+	if !IsRetryable(dl) || !IsRetryable(lw) || IsRetryable(dup) {
+		t.Error("IsRetryable")
+	}
+}
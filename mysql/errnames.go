@@ -0,0 +1,483 @@
+// Code generated by mkerrnames.go; DO NOT EDIT.
+
+package mysql
+
+// Maps MySQL error codes to their symbolic names
+var errorNames = map[uint16]string{
+	1000: "ER_HASHCHK",
+	1001: "ER_NISAMCHK",
+	1002: "ER_NO",
+	1003: "ER_YES",
+	1004: "ER_CANT_CREATE_FILE",
+	1005: "ER_CANT_CREATE_TABLE",
+	1006: "ER_CANT_CREATE_DB",
+	1007: "ER_DB_CREATE_EXISTS",
+	1008: "ER_DB_DROP_EXISTS",
+	1009: "ER_DB_DROP_DELETE",
+	1010: "ER_DB_DROP_RMDIR",
+	1011: "ER_CANT_DELETE_FILE",
+	1012: "ER_CANT_FIND_SYSTEM_REC",
+	1013: "ER_CANT_GET_STAT",
+	1014: "ER_CANT_GET_WD",
+	1015: "ER_CANT_LOCK",
+	1016: "ER_CANT_OPEN_FILE",
+	1017: "ER_FILE_NOT_FOUND",
+	1018: "ER_CANT_READ_DIR",
+	1019: "ER_CANT_SET_WD",
+	1020: "ER_CHECKREAD",
+	1021: "ER_DISK_FULL",
+	1022: "ER_DUP_KEY",
+	1023: "ER_ERROR_ON_CLOSE",
+	1024: "ER_ERROR_ON_READ",
+	1025: "ER_ERROR_ON_RENAME",
+	1026: "ER_ERROR_ON_WRITE",
+	1027: "ER_FILE_USED",
+	1028: "ER_FILSORT_ABORT",
+	1029: "ER_FORM_NOT_FOUND",
+	1030: "ER_GET_ERRNO",
+	1031: "ER_ILLEGAL_HA",
+	1032: "ER_KEY_NOT_FOUND",
+	1033: "ER_NOT_FORM_FILE",
+	1034: "ER_NOT_KEYFILE",
+	1035: "ER_OLD_KEYFILE",
+	1036: "ER_OPEN_AS_READONLY",
+	1037: "ER_OUTOFMEMORY",
+	1038: "ER_OUT_OF_SORTMEMORY",
+	1039: "ER_UNEXPECTED_EOF",
+	1040: "ER_CON_COUNT_ERROR",
+	1041: "ER_OUT_OF_RESOURCES",
+	1042: "ER_BAD_HOST_ERROR",
+	1043: "ER_HANDSHAKE_ERROR",
+	1044: "ER_DBACCESS_DENIED_ERROR",
+	1045: "ER_ACCESS_DENIED_ERROR",
+	1046: "ER_NO_DB_ERROR",
+	1047: "ER_UNKNOWN_COM_ERROR",
+	1048: "ER_BAD_NULL_ERROR",
+	1049: "ER_BAD_DB_ERROR",
+	1050: "ER_TABLE_EXISTS_ERROR",
+	1051: "ER_BAD_TABLE_ERROR",
+	1052: "ER_NON_UNIQ_ERROR",
+	1053: "ER_SERVER_SHUTDOWN",
+	1054: "ER_BAD_FIELD_ERROR",
+	1055: "ER_WRONG_FIELD_WITH_GROUP",
+	1056: "ER_WRONG_GROUP_FIELD",
+	1057: "ER_WRONG_SUM_SELECT",
+	1058: "ER_WRONG_VALUE_COUNT",
+	1059: "ER_TOO_LONG_IDENT",
+	1060: "ER_DUP_FIELDNAME",
+	1061: "ER_DUP_KEYNAME",
+	1062: "ER_DUP_ENTRY",
+	1063: "ER_WRONG_FIELD_SPEC",
+	1064: "ER_PARSE_ERROR",
+	1065: "ER_EMPTY_QUERY",
+	1066: "ER_NONUNIQ_TABLE",
+	1067: "ER_INVALID_DEFAULT",
+	1068: "ER_MULTIPLE_PRI_KEY",
+	1069: "ER_TOO_MANY_KEYS",
+	1070: "ER_TOO_MANY_KEY_PARTS",
+	1071: "ER_TOO_LONG_KEY",
+	1072: "ER_KEY_COLUMN_DOES_NOT_EXITS",
+	1073: "ER_BLOB_USED_AS_KEY",
+	1074: "ER_TOO_BIG_FIELDLENGTH",
+	1075: "ER_WRONG_AUTO_KEY",
+	1076: "ER_READY",
+	1077: "ER_NORMAL_SHUTDOWN",
+	1078: "ER_GOT_SIGNAL",
+	1079: "ER_SHUTDOWN_COMPLETE",
+	1080: "ER_FORCING_CLOSE",
+	1081: "ER_IPSOCK_ERROR",
+	1082: "ER_NO_SUCH_INDEX",
+	1083: "ER_WRONG_FIELD_TERMINATORS",
+	1084: "ER_BLOBS_AND_NO_TERMINATED",
+	1085: "ER_TEXTFILE_NOT_READABLE",
+	1086: "ER_FILE_EXISTS_ERROR",
+	1087: "ER_LOAD_INFO",
+	1088: "ER_ALTER_INFO",
+	1089: "ER_WRONG_SUB_KEY",
+	1090: "ER_CANT_REMOVE_ALL_FIELDS",
+	1091: "ER_CANT_DROP_FIELD_OR_KEY",
+	1092: "ER_INSERT_INFO",
+	1093: "ER_UPDATE_TABLE_USED",
+	1094: "ER_NO_SUCH_THREAD",
+	1095: "ER_KILL_DENIED_ERROR",
+	1096: "ER_NO_TABLES_USED",
+	1097: "ER_TOO_BIG_SET",
+	1098: "ER_NO_UNIQUE_LOGFILE",
+	1099: "ER_TABLE_NOT_LOCKED_FOR_WRITE",
+	1100: "ER_TABLE_NOT_LOCKED",
+	1101: "ER_BLOB_CANT_HAVE_DEFAULT",
+	1102: "ER_WRONG_DB_NAME",
+	1103: "ER_WRONG_TABLE_NAME",
+	1104: "ER_TOO_BIG_SELECT",
+	1105: "ER_UNKNOWN_ERROR",
+	1106: "ER_UNKNOWN_PROCEDURE",
+	1107: "ER_WRONG_PARAMCOUNT_TO_PROCEDURE",
+	1108: "ER_WRONG_PARAMETERS_TO_PROCEDURE",
+	1109: "ER_UNKNOWN_TABLE",
+	1110: "ER_FIELD_SPECIFIED_TWICE",
+	1111: "ER_INVALID_GROUP_FUNC_USE",
+	1112: "ER_UNSUPPORTED_EXTENSION",
+	1113: "ER_TABLE_MUST_HAVE_COLUMNS",
+	1114: "ER_RECORD_FILE_FULL",
+	1115: "ER_UNKNOWN_CHARACTER_SET",
+	1116: "ER_TOO_MANY_TABLES",
+	1117: "ER_TOO_MANY_FIELDS",
+	1118: "ER_TOO_BIG_ROWSIZE",
+	1119: "ER_STACK_OVERRUN",
+	1120: "ER_WRONG_OUTER_JOIN",
+	1121: "ER_NULL_COLUMN_IN_INDEX",
+	1122: "ER_CANT_FIND_UDF",
+	1123: "ER_CANT_INITIALIZE_UDF",
+	1124: "ER_UDF_NO_PATHS",
+	1125: "ER_UDF_EXISTS",
+	1126: "ER_CANT_OPEN_LIBRARY",
+	1127: "ER_CANT_FIND_DL_ENTRY",
+	1128: "ER_FUNCTION_NOT_DEFINED",
+	1129: "ER_HOST_IS_BLOCKED",
+	1130: "ER_HOST_NOT_PRIVILEGED",
+	1131: "ER_PASSWORD_ANONYMOUS_USER",
+	1132: "ER_PASSWORD_NOT_ALLOWED",
+	1133: "ER_PASSWORD_NO_MATCH",
+	1134: "ER_UPDATE_INFO",
+	1135: "ER_CANT_CREATE_THREAD",
+	1136: "ER_WRONG_VALUE_COUNT_ON_ROW",
+	1137: "ER_CANT_REOPEN_TABLE",
+	1138: "ER_INVALID_USE_OF_NULL",
+	1139: "ER_REGEXP_ERROR",
+	1140: "ER_MIX_OF_GROUP_FUNC_AND_FIELDS",
+	1141: "ER_NONEXISTING_GRANT",
+	1142: "ER_TABLEACCESS_DENIED_ERROR",
+	1143: "ER_COLUMNACCESS_DENIED_ERROR",
+	1144: "ER_ILLEGAL_GRANT_FOR_TABLE",
+	1145: "ER_GRANT_WRONG_HOST_OR_USER",
+	1146: "ER_NO_SUCH_TABLE",
+	1147: "ER_NONEXISTING_TABLE_GRANT",
+	1148: "ER_NOT_ALLOWED_COMMAND",
+	1149: "ER_SYNTAX_ERROR",
+	1150: "ER_DELAYED_CANT_CHANGE_LOCK",
+	1151: "ER_TOO_MANY_DELAYED_THREADS",
+	1152: "ER_ABORTING_CONNECTION",
+	1153: "ER_NET_PACKET_TOO_LARGE",
+	1154: "ER_NET_READ_ERROR_FROM_PIPE",
+	1155: "ER_NET_FCNTL_ERROR",
+	1156: "ER_NET_PACKETS_OUT_OF_ORDER",
+	1157: "ER_NET_UNCOMPRESS_ERROR",
+	1158: "ER_NET_READ_ERROR",
+	1159: "ER_NET_READ_INTERRUPTED",
+	1160: "ER_NET_ERROR_ON_WRITE",
+	1161: "ER_NET_WRITE_INTERRUPTED",
+	1162: "ER_TOO_LONG_STRING",
+	1163: "ER_TABLE_CANT_HANDLE_BLOB",
+	1164: "ER_TABLE_CANT_HANDLE_AUTO_INCREMENT",
+	1165: "ER_DELAYED_INSERT_TABLE_LOCKED",
+	1166: "ER_WRONG_COLUMN_NAME",
+	1167: "ER_WRONG_KEY_COLUMN",
+	1168: "ER_WRONG_MRG_TABLE",
+	1169: "ER_DUP_UNIQUE",
+	1170: "ER_BLOB_KEY_WITHOUT_LENGTH",
+	1171: "ER_PRIMARY_CANT_HAVE_NULL",
+	1172: "ER_TOO_MANY_ROWS",
+	1173: "ER_REQUIRES_PRIMARY_KEY",
+	1174: "ER_NO_RAID_COMPILED",
+	1175: "ER_UPDATE_WITHOUT_KEY_IN_SAFE_MODE",
+	1176: "ER_KEY_DOES_NOT_EXITS",
+	1177: "ER_CHECK_NO_SUCH_TABLE",
+	1178: "ER_CHECK_NOT_IMPLEMENTED",
+	1179: "ER_CANT_DO_THIS_DURING_AN_TRANSACTION",
+	1180: "ER_ERROR_DURING_COMMIT",
+	1181: "ER_ERROR_DURING_ROLLBACK",
+	1182: "ER_ERROR_DURING_FLUSH_LOGS",
+	1183: "ER_ERROR_DURING_CHECKPOINT",
+	1184: "ER_NEW_ABORTING_CONNECTION",
+	1185: "ER_DUMP_NOT_IMPLEMENTED",
+	1186: "ER_FLUSH_MASTER_BINLOG_CLOSED",
+	1187: "ER_INDEX_REBUILD",
+	1188: "ER_MASTER",
+	1189: "ER_MASTER_NET_READ",
+	1190: "ER_MASTER_NET_WRITE",
+	1191: "ER_FT_MATCHING_KEY_NOT_FOUND",
+	1192: "ER_LOCK_OR_ACTIVE_TRANSACTION",
+	1193: "ER_UNKNOWN_SYSTEM_VARIABLE",
+	1194: "ER_CRASHED_ON_USAGE",
+	1195: "ER_CRASHED_ON_REPAIR",
+	1196: "ER_WARNING_NOT_COMPLETE_ROLLBACK",
+	1197: "ER_TRANS_CACHE_FULL",
+	1198: "ER_SLAVE_MUST_STOP",
+	1199: "ER_SLAVE_NOT_RUNNING",
+	1200: "ER_BAD_SLAVE",
+	1201: "ER_MASTER_INFO",
+	1202: "ER_SLAVE_THREAD",
+	1203: "ER_TOO_MANY_USER_CONNECTIONS",
+	1204: "ER_SET_CONSTANTS_ONLY",
+	1205: "ER_LOCK_WAIT_TIMEOUT",
+	1206: "ER_LOCK_TABLE_FULL",
+	1207: "ER_READ_ONLY_TRANSACTION",
+	1208: "ER_DROP_DB_WITH_READ_LOCK",
+	1209: "ER_CREATE_DB_WITH_READ_LOCK",
+	1210: "ER_WRONG_ARGUMENTS",
+	1211: "ER_NO_PERMISSION_TO_CREATE_USER",
+	1212: "ER_UNION_TABLES_IN_DIFFERENT_DIR",
+	1213: "ER_LOCK_DEADLOCK",
+	1214: "ER_TABLE_CANT_HANDLE_FT",
+	1215: "ER_CANNOT_ADD_FOREIGN",
+	1216: "ER_NO_REFERENCED_ROW",
+	1217: "ER_ROW_IS_REFERENCED",
+	1218: "ER_CONNECT_TO_MASTER",
+	1219: "ER_QUERY_ON_MASTER",
+	1220: "ER_ERROR_WHEN_EXECUTING_COMMAND",
+	1221: "ER_WRONG_USAGE",
+	1222: "ER_WRONG_NUMBER_OF_COLUMNS_IN_SELECT",
+	1223: "ER_CANT_UPDATE_WITH_READLOCK",
+	1224: "ER_MIXING_NOT_ALLOWED",
+	1225: "ER_DUP_ARGUMENT",
+	1226: "ER_USER_LIMIT_REACHED",
+	1227: "ER_SPECIFIC_ACCESS_DENIED_ERROR",
+	1228: "ER_LOCAL_VARIABLE",
+	1229: "ER_GLOBAL_VARIABLE",
+	1230: "ER_NO_DEFAULT",
+	1231: "ER_WRONG_VALUE_FOR_VAR",
+	1232: "ER_WRONG_TYPE_FOR_VAR",
+	1233: "ER_VAR_CANT_BE_READ",
+	1234: "ER_CANT_USE_OPTION_HERE",
+	1235: "ER_NOT_SUPPORTED_YET",
+	1236: "ER_MASTER_FATAL_ERROR_READING_BINLOG",
+	1237: "ER_SLAVE_IGNORED_TABLE",
+	1238: "ER_INCORRECT_GLOBAL_LOCAL_VAR",
+	1239: "ER_WRONG_FK_DEF",
+	1240: "ER_KEY_REF_DO_NOT_MATCH_TABLE_REF",
+	1241: "ER_OPERAND_COLUMNS",
+	1242: "ER_SUBQUERY_NO_1_ROW",
+	1243: "ER_UNKNOWN_STMT_HANDLER",
+	1244: "ER_CORRUPT_HELP_DB",
+	1245: "ER_CYCLIC_REFERENCE",
+	1246: "ER_AUTO_CONVERT",
+	1247: "ER_ILLEGAL_REFERENCE",
+	1248: "ER_DERIVED_MUST_HAVE_ALIAS",
+	1249: "ER_SELECT_REDUCED",
+	1250: "ER_TABLENAME_NOT_ALLOWED_HERE",
+	1251: "ER_NOT_SUPPORTED_AUTH_MODE",
+	1252: "ER_SPATIAL_CANT_HAVE_NULL",
+	1253: "ER_COLLATION_CHARSET_MISMATCH",
+	1254: "ER_SLAVE_WAS_RUNNING",
+	1255: "ER_SLAVE_WAS_NOT_RUNNING",
+	1256: "ER_TOO_BIG_FOR_UNCOMPRESS",
+	1257: "ER_ZLIB_Z_MEM_ERROR",
+	1258: "ER_ZLIB_Z_BUF_ERROR",
+	1259: "ER_ZLIB_Z_DATA_ERROR",
+	1260: "ER_CUT_VALUE_GROUP_CONCAT",
+	1261: "ER_WARN_TOO_FEW_RECORDS",
+	1262: "ER_WARN_TOO_MANY_RECORDS",
+	1263: "ER_WARN_NULL_TO_NOTNULL",
+	1264: "ER_WARN_DATA_OUT_OF_RANGE",
+	1265: "WARN_DATA_TRUNCATED",
+	1266: "ER_WARN_USING_OTHER_HANDLER",
+	1267: "ER_CANT_AGGREGATE_2COLLATIONS",
+	1268: "ER_DROP_USER",
+	1269: "ER_REVOKE_GRANTS",
+	1270: "ER_CANT_AGGREGATE_3COLLATIONS",
+	1271: "ER_CANT_AGGREGATE_NCOLLATIONS",
+	1272: "ER_VARIABLE_IS_NOT_STRUCT",
+	1273: "ER_UNKNOWN_COLLATION",
+	1274: "ER_SLAVE_IGNORED_SSL_PARAMS",
+	1275: "ER_SERVER_IS_IN_SECURE_AUTH_MODE",
+	1276: "ER_WARN_FIELD_RESOLVED",
+	1277: "ER_BAD_SLAVE_UNTIL_COND",
+	1278: "ER_MISSING_SKIP_SLAVE",
+	1279: "ER_UNTIL_COND_IGNORED",
+	1280: "ER_WRONG_NAME_FOR_INDEX",
+	1281: "ER_WRONG_NAME_FOR_CATALOG",
+	1282: "ER_WARN_QC_RESIZE",
+	1283: "ER_BAD_FT_COLUMN",
+	1284: "ER_UNKNOWN_KEY_CACHE",
+	1285: "ER_WARN_HOSTNAME_WONT_WORK",
+	1286: "ER_UNKNOWN_STORAGE_ENGINE",
+	1287: "ER_WARN_DEPRECATED_SYNTAX",
+	1288: "ER_NON_UPDATABLE_TABLE",
+	1289: "ER_FEATURE_DISABLED",
+	1290: "ER_OPTION_PREVENTS_STATEMENT",
+	1291: "ER_DUPLICATED_VALUE_IN_TYPE",
+	1292: "ER_TRUNCATED_WRONG_VALUE",
+	1293: "ER_TOO_MUCH_AUTO_TIMESTAMP_COLS",
+	1294: "ER_INVALID_ON_UPDATE",
+	1295: "ER_UNSUPPORTED_PS",
+	1296: "ER_GET_ERRMSG",
+	1297: "ER_GET_TEMPORARY_ERRMSG",
+	1298: "ER_UNKNOWN_TIME_ZONE",
+	1299: "ER_WARN_INVALID_TIMESTAMP",
+	1300: "ER_INVALID_CHARACTER_STRING",
+	1301: "ER_WARN_ALLOWED_PACKET_OVERFLOWED",
+	1302: "ER_CONFLICTING_DECLARATIONS",
+	1303: "ER_SP_NO_RECURSIVE_CREATE",
+	1304: "ER_SP_ALREADY_EXISTS",
+	1305: "ER_SP_DOES_NOT_EXIST",
+	1306: "ER_SP_DROP_FAILED",
+	1307: "ER_SP_STORE_FAILED",
+	1308: "ER_SP_LILABEL_MISMATCH",
+	1309: "ER_SP_LABEL_REDEFINE",
+	1310: "ER_SP_LABEL_MISMATCH",
+	1311: "ER_SP_UNINIT_VAR",
+	1312: "ER_SP_BADSELECT",
+	1313: "ER_SP_BADRETURN",
+	1314: "ER_SP_BADSTATEMENT",
+	1315: "ER_UPDATE_LOG_DEPRECATED_IGNORED",
+	1316: "ER_UPDATE_LOG_DEPRECATED_TRANSLATED",
+	1317: "ER_QUERY_INTERRUPTED",
+	1318: "ER_SP_WRONG_NO_OF_ARGS",
+	1319: "ER_SP_COND_MISMATCH",
+	1320: "ER_SP_NORETURN",
+	1321: "ER_SP_NORETURNEND",
+	1322: "ER_SP_BAD_CURSOR_QUERY",
+	1323: "ER_SP_BAD_CURSOR_SELECT",
+	1324: "ER_SP_CURSOR_MISMATCH",
+	1325: "ER_SP_CURSOR_ALREADY_OPEN",
+	1326: "ER_SP_CURSOR_NOT_OPEN",
+	1327: "ER_SP_UNDECLARED_VAR",
+	1328: "ER_SP_WRONG_NO_OF_FETCH_ARGS",
+	1329: "ER_SP_FETCH_NO_DATA",
+	1330: "ER_SP_DUP_PARAM",
+	1331: "ER_SP_DUP_VAR",
+	1332: "ER_SP_DUP_COND",
+	1333: "ER_SP_DUP_CURS",
+	1334: "ER_SP_CANT_ALTER",
+	1335: "ER_SP_SUBSELECT_NYI",
+	1336: "ER_STMT_NOT_ALLOWED_IN_SF_OR_TRG",
+	1337: "ER_SP_VARCOND_AFTER_CURSHNDLR",
+	1338: "ER_SP_CURSOR_AFTER_HANDLER",
+	1339: "ER_SP_CASE_NOT_FOUND",
+	1340: "ER_FPARSER_TOO_BIG_FILE",
+	1341: "ER_FPARSER_BAD_HEADER",
+	1342: "ER_FPARSER_EOF_IN_COMMENT",
+	1343: "ER_FPARSER_ERROR_IN_PARAMETER",
+	1344: "ER_FPARSER_EOF_IN_UNKNOWN_PARAMETER",
+	1345: "ER_VIEW_NO_EXPLAIN",
+	1346: "ER_FRM_UNKNOWN_TYPE",
+	1347: "ER_WRONG_OBJECT",
+	1348: "ER_NONUPDATEABLE_COLUMN",
+	1349: "ER_VIEW_SELECT_DERIVED",
+	1350: "ER_VIEW_SELECT_CLAUSE",
+	1351: "ER_VIEW_SELECT_VARIABLE",
+	1352: "ER_VIEW_SELECT_TMPTABLE",
+	1353: "ER_VIEW_WRONG_LIST",
+	1354: "ER_WARN_VIEW_MERGE",
+	1355: "ER_WARN_VIEW_WITHOUT_KEY",
+	1356: "ER_VIEW_INVALID",
+	1357: "ER_SP_NO_DROP_SP",
+	1358: "ER_SP_GOTO_IN_HNDLR",
+	1359: "ER_TRG_ALREADY_EXISTS",
+	1360: "ER_TRG_DOES_NOT_EXIST",
+	1361: "ER_TRG_ON_VIEW_OR_TEMP_TABLE",
+	1362: "ER_TRG_CANT_CHANGE_ROW",
+	1363: "ER_TRG_NO_SUCH_ROW_IN_TRG",
+	1364: "ER_NO_DEFAULT_FOR_FIELD",
+	1365: "ER_DIVISION_BY_ZERO",
+	1366: "ER_TRUNCATED_WRONG_VALUE_FOR_FIELD",
+	1367: "ER_ILLEGAL_VALUE_FOR_TYPE",
+	1368: "ER_VIEW_NONUPD_CHECK",
+	1369: "ER_VIEW_CHECK_FAILED",
+	1370: "ER_PROCACCESS_DENIED_ERROR",
+	1371: "ER_RELAY_LOG_FAIL",
+	1372: "ER_PASSWD_LENGTH",
+	1373: "ER_UNKNOWN_TARGET_BINLOG",
+	1374: "ER_IO_ERR_LOG_INDEX_READ",
+	1375: "ER_BINLOG_PURGE_PROHIBITED",
+	1376: "ER_FSEEK_FAIL",
+	1377: "ER_BINLOG_PURGE_FATAL_ERR",
+	1378: "ER_LOG_IN_USE",
+	1379: "ER_LOG_PURGE_UNKNOWN_ERR",
+	1380: "ER_RELAY_LOG_INIT",
+	1381: "ER_NO_BINARY_LOGGING",
+	1382: "ER_RESERVED_SYNTAX",
+	1383: "ER_WSAS_FAILED",
+	1384: "ER_DIFF_GROUPS_PROC",
+	1385: "ER_NO_GROUP_FOR_PROC",
+	1386: "ER_ORDER_WITH_PROC",
+	1387: "ER_LOGGING_PROHIBIT_CHANGING_OF",
+	1388: "ER_NO_FILE_MAPPING",
+	1389: "ER_WRONG_MAGIC",
+	1390: "ER_PS_MANY_PARAM",
+	1391: "ER_KEY_PART_0",
+	1392: "ER_VIEW_CHECKSUM",
+	1393: "ER_VIEW_MULTIUPDATE",
+	1394: "ER_VIEW_NO_INSERT_FIELD_LIST",
+	1395: "ER_VIEW_DELETE_MERGE_VIEW",
+	1396: "ER_CANNOT_USER",
+	1397: "ER_XAER_NOTA",
+	1398: "ER_XAER_INVAL",
+	1399: "ER_XAER_RMFAIL",
+	1400: "ER_XAER_OUTSIDE",
+	1401: "ER_XAER_RMERR",
+	1402: "ER_XA_RBROLLBACK",
+	1403: "ER_NONEXISTING_PROC_GRANT",
+	1404: "ER_PROC_AUTO_GRANT_FAIL",
+	1405: "ER_PROC_AUTO_REVOKE_FAIL",
+	1406: "ER_DATA_TOO_LONG",
+	1407: "ER_SP_BAD_SQLSTATE",
+	1408: "ER_STARTUP",
+	1409: "ER_LOAD_FROM_FIXED_SIZE_ROWS_TO_VAR",
+	1410: "ER_CANT_CREATE_USER_WITH_GRANT",
+	1411: "ER_WRONG_VALUE_FOR_TYPE",
+	1412: "ER_TABLE_DEF_CHANGED",
+	1413: "ER_SP_DUP_HANDLER",
+	1414: "ER_SP_NOT_VAR_ARG",
+	1415: "ER_SP_NO_RETSET",
+	1416: "ER_CANT_CREATE_GEOMETRY_OBJECT",
+	1417: "ER_FAILED_ROUTINE_BREAK_BINLOG",
+	1418: "ER_BINLOG_UNSAFE_ROUTINE",
+	1419: "ER_BINLOG_CREATE_ROUTINE_NEED_SUPER",
+	1420: "ER_EXEC_STMT_WITH_OPEN_CURSOR",
+	1421: "ER_STMT_HAS_NO_OPEN_CURSOR",
+	1422: "ER_COMMIT_NOT_ALLOWED_IN_SF_OR_TRG",
+	1423: "ER_NO_DEFAULT_FOR_VIEW_FIELD",
+	1424: "ER_SP_NO_RECURSION",
+	1425: "ER_TOO_BIG_SCALE",
+	1426: "ER_TOO_BIG_PRECISION",
+	1427: "ER_M_BIGGER_THAN_D",
+	1428: "ER_WRONG_LOCK_OF_SYSTEM_TABLE",
+	1429: "ER_CONNECT_TO_FOREIGN_DATA_SOURCE",
+	1430: "ER_QUERY_ON_FOREIGN_DATA_SOURCE",
+	1431: "ER_FOREIGN_DATA_SOURCE_DOESNT_EXIST",
+	1432: "ER_FOREIGN_DATA_STRING_INVALID_CANT_CREATE",
+	1433: "ER_FOREIGN_DATA_STRING_INVALID",
+	1434: "ER_CANT_CREATE_FEDERATED_TABLE",
+	1435: "ER_TRG_IN_WRONG_SCHEMA",
+	1436: "ER_STACK_OVERRUN_NEED_MORE",
+	1437: "ER_TOO_LONG_BODY",
+	1438: "ER_WARN_CANT_DROP_DEFAULT_KEYCACHE",
+	1439: "ER_TOO_BIG_DISPLAYWIDTH",
+	1440: "ER_XAER_DUPID",
+	1441: "ER_DATETIME_FUNCTION_OVERFLOW",
+	1442: "ER_CANT_UPDATE_USED_TABLE_IN_SF_OR_TRG",
+	1443: "ER_VIEW_PREVENT_UPDATE",
+	1444: "ER_PS_NO_RECURSION",
+	1445: "ER_SP_CANT_SET_AUTOCOMMIT",
+	1446: "ER_MALFORMED_DEFINER",
+	1447: "ER_VIEW_FRM_NO_USER",
+	1448: "ER_VIEW_OTHER_USER",
+	1449: "ER_NO_SUCH_USER",
+	1450: "ER_FORBID_SCHEMA_CHANGE",
+	1451: "ER_ROW_IS_REFERENCED_2",
+	1452: "ER_NO_REFERENCED_ROW_2",
+	1453: "ER_SP_BAD_VAR_SHADOW",
+	1454: "ER_TRG_NO_DEFINER",
+	1455: "ER_OLD_FILE_FORMAT",
+	1456: "ER_SP_RECURSION_LIMIT",
+	1457: "ER_SP_PROC_TABLE_CORRUPT",
+	1458: "ER_SP_WRONG_NAME",
+	1459: "ER_TABLE_NEEDS_UPGRADE",
+	1460: "ER_SP_NO_AGGREGATE",
+	1461: "ER_MAX_PREPARED_STMT_COUNT_REACHED",
+	1462: "ER_VIEW_RECURSIVE",
+	1463: "ER_NON_GROUPING_FIELD_USED",
+	1464: "ER_TABLE_CANT_HANDLE_SPKEYS",
+	1465: "ER_NO_TRIGGERS_ON_SYSTEM_SCHEMA",
+	1466: "ER_REMOVED_SPACES",
+	1467: "ER_AUTOINC_READ_FAILED",
+	1468: "ER_USERNAME",
+	1469: "ER_HOSTNAME",
+	1470: "ER_WRONG_STRING_LENGTH",
+	1471: "ER_NON_INSERTABLE_TABLE",
+	1586: "ER_DUP_ENTRY_WITH_KEY_NAME",
+	1615: "ER_NEED_REPREPARE",
+	1792: "ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION",
+	1836: "ER_READ_ONLY_MODE",
+}
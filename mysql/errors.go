@@ -12,16 +12,61 @@ import (
 //     if val, ok := err.(*mysql.Error); ok {
 //         fmt.Println(val.Code)
 //     }
+//
+// SqlState contains five characters SQLSTATE value (eg: "23000") or is empty
+// if the server didn't send it. See also IsDuplicateKey, IsDeadlock,... for
+// error classification.
 type Error struct {
-	Code uint16
-	Msg  []byte
+	Code     uint16
+	SqlState string
+	Msg      []byte
 }
 
 func (err Error) Error() string {
-	return fmt.Sprintf("Received #%d error from MySQL server: \"%s\"",
-		err.Code, err.Msg)
+	id := ErrorName(err.Code)
+	if id == "" {
+		id = fmt.Sprintf("#%d", err.Code)
+		if err.SqlState != "" {
+			id += " (" + err.SqlState + ")"
+		}
+	} else if err.SqlState != "" {
+		id += fmt.Sprintf(" (%d, %s)", err.Code, err.SqlState)
+	} else {
+		id += fmt.Sprintf(" (%d)", err.Code)
+	}
+	return fmt.Sprintf("Received %s error from MySQL server: \"%s\"",
+		id, err.Msg)
+}
+
+// Returns symbolic name of the MySQL error code (eg: "ER_DUP_ENTRY" for 1062)
+// or empty string if the code is unknown.
+func ErrorName(code uint16) string {
+	return errorNames[code]
 }
 
+// Warning returned by SHOW WARNINGS. See Conn.SetAutoWarnings and
+// Result.Warnings.
+type Warning struct {
+	Level   string // Note, Warning or Error
+	Code    uint16
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s %d: %s", w.Level, w.Code, w.Message)
+}
+
+// Codes of warnings about data truncation or conversion. You can pass them to
+// Conn.SetStrictWarnings.
+var TruncationWarnings = []uint16{
+	ER_WARN_DATA_OUT_OF_RANGE,
+	WARN_DATA_TRUNCATED,
+	ER_TRUNCATED_WRONG_VALUE,
+	ER_TRUNCATED_WRONG_VALUE_FOR_FIELD,
+}
+
+//go:generate go run mkerrnames.go
+
 // MySQL error codes
 const (
 	ER_HASHCHK                                 = 1000
@@ -496,4 +541,10 @@ const (
 	ER_HOSTNAME                                = 1469
 	ER_WRONG_STRING_LENGTH                     = 1470
 	ER_NON_INSERTABLE_TABLE                    = 1471
+
+	// MySQL 5.1 and later (only selected codes)
+	ER_DUP_ENTRY_WITH_KEY_NAME               = 1586
+	ER_NEED_REPREPARE                        = 1615
+	ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION = 1792
+	ER_READ_ONLY_MODE                        = 1836
 )
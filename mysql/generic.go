@@ -0,0 +1,170 @@
+package mysql
+
+import (
+	"errors"
+	"io"
+	"reflect"
+)
+
+var (
+	NO_ROWS_ERROR       = errors.New("mysql: no rows in result set")
+	MULTIPLE_ROWS_ERROR = errors.New("mysql: more than one row in result set")
+	COLUMN_COUNT_ERROR  = errors.New("mysql: scalar type requires one column")
+)
+
+// Returns function that decodes a row into *T. If T is a struct (or pointer
+// to struct) rows are decoded as in ScanStruct, otherwise the column with
+// index col is decoded using the Row conversion rules. If col < 0 the result
+// must have exactly one column.
+func makeDecoder[T any](r Result, col int) (func(Row, *T) error, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if col < 0 && st.Kind() == reflect.Struct && !isStructValue(st) {
+		cp := makeColPlan(r.Fields(), st)
+		if t != st {
+			return func(row Row, dst *T) error {
+				p := reflect.New(st)
+				if err := cp.scan(row, p.Elem()); err != nil {
+					return err
+				}
+				reflect.ValueOf(dst).Elem().Set(p)
+				return nil
+			}, nil
+		}
+		return func(row Row, dst *T) error {
+			return cp.scan(row, reflect.ValueOf(dst).Elem())
+		}, nil
+	}
+	set := makeSetter(t)
+	if set == nil {
+		return nil, errors.New("mysql: unsupported type " + t.String())
+	}
+	if col < 0 {
+		if len(r.Fields()) != 1 {
+			return nil, COLUMN_COUNT_ERROR
+		}
+		col = 0
+	} else if col >= len(r.Fields()) {
+		return nil, COLUMN_COUNT_ERROR
+	}
+	return func(row Row, dst *T) error {
+		return set(reflect.ValueOf(dst).Elem(), row, col)
+	}, nil
+}
+
+// Reads all rows from the result and decodes them as T.
+func readRows[T any](r Result) (rows []T, err error) {
+	if r.StatusOnly() {
+		return
+	}
+	dec, err := makeDecoder[T](r, -1)
+	if err != nil {
+		r.End()
+		return
+	}
+	row := r.MakeRow()
+	for {
+		if err = r.ScanRow(row); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		var v T
+		if err = dec(row, &v); err != nil {
+			r.End()
+			return
+		}
+		rows = append(rows, v)
+	}
+}
+
+// Reads exactly one row from the result and decodes it (or its col column if
+// col >= 0) as T.
+func readOne[T any](r Result, col int) (val T, err error) {
+	if r.StatusOnly() {
+		err = NO_ROWS_ERROR
+		return
+	}
+	dec, err := makeDecoder[T](r, col)
+	if err != nil {
+		r.End()
+		return
+	}
+	row := r.MakeRow()
+	if err = r.ScanRow(row); err != nil {
+		if err == io.EOF {
+			err = NO_ROWS_ERROR
+		}
+		return
+	}
+	if err = dec(row, &val); err != nil {
+		r.End()
+		return
+	}
+	switch err = r.ScanRow(row); err {
+	case io.EOF:
+		err = nil
+	case nil:
+		r.End()
+		err = MULTIPLE_ROWS_ERROR
+	}
+	return
+}
+
+// Calls Start and decodes all rows of the result as T. T may be a struct (or
+// a pointer to struct), then rows are decoded as in ScanStruct. Otherwise the
+// result must have one column, that is converted to T using the same rules
+// as for struct fields.
+func QueryRows[T any](c ConnCommon, sql string, params ...interface{}) ([]T, error) {
+	res, err := c.Start(sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	return readRows[T](res)
+}
+
+// Like QueryRows but the result must contain exactly one row. Returns
+// NO_ROWS_ERROR or MULTIPLE_ROWS_ERROR otherwise.
+func QueryOne[T any](c ConnCommon, sql string, params ...interface{}) (T, error) {
+	res, err := c.Start(sql, params...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return readOne[T](res, -1)
+}
+
+// Calls Start and returns the first column of the only row of the result
+// converted to T. Returns NO_ROWS_ERROR or MULTIPLE_ROWS_ERROR if the result
+// doesn't contain exactly one row.
+func Scalar[T any](c ConnCommon, sql string, params ...interface{}) (T, error) {
+	res, err := c.Start(sql, params...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return readOne[T](res, 0)
+}
+
+// Calls Run and decodes all rows of the result as T (see QueryRows).
+func ExecRows[T any](s Stmt, params ...interface{}) ([]T, error) {
+	res, err := s.Run(params...)
+	if err != nil {
+		return nil, err
+	}
+	return readRows[T](res)
+}
+
+// Calls Run and decodes the only row of the result as T (see QueryOne).
+func ExecOne[T any](s Stmt, params ...interface{}) (T, error) {
+	res, err := s.Run(params...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return readOne[T](res, -1)
+}
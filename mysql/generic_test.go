@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+// Result that returns rows from memory. Methods not used by readRows and
+// readOne aren't implemented.
+type memResult struct {
+	Result
+	fields []*Field
+	rows   []Row
+}
+
+func (r *memResult) StatusOnly() bool { return len(r.fields) == 0 }
+func (r *memResult) Fields() []*Field { return r.fields }
+func (r *memResult) MakeRow() Row     { return make(Row, len(r.fields)) }
+func (r *memResult) End() error       { r.rows = nil; return nil }
+
+func (r *memResult) ScanRow(row Row) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(row, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestReadRows(t *testing.T) {
+	type user struct {
+		Id   int
+		Name string
+	}
+	res := &memResult{
+		fields: scanFields("id", "name"),
+		rows:   []Row{{[]byte("1"), []byte("a")}, {int64(2), []byte("b")}},
+	}
+	users, err := readRows[*user](res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []*user{{1, "a"}, {2, "b"}}
+	if !reflect.DeepEqual(users, exp) {
+		t.Fatalf("ret=%+v exp=%+v", users, exp)
+	}
+
+	res = &memResult{
+		fields: scanFields("n"),
+		rows:   []Row{{[]byte("10")}, {nil}},
+	}
+	ns, err := readRows[*int64](res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ns) != 2 || *ns[0] != 10 || ns[1] != nil {
+		t.Fatalf("Bad scalar rows: %v", ns)
+	}
+
+	res = &memResult{fields: scanFields("a", "b"), rows: []Row{{nil, nil}}}
+	if _, err = readRows[int](res); err != COLUMN_COUNT_ERROR {
+		t.Fatalf("Expected COLUMN_COUNT_ERROR, got: %v", err)
+	}
+}
+
+func TestReadOne(t *testing.T) {
+	res := &memResult{fields: scanFields("a", "b"), rows: []Row{{"x", int8(3)}}}
+	s, err := readOne[string](res, 0)
+	if err != nil || s != "x" {
+		t.Fatalf("readOne: ret=%q err=%v", s, err)
+	}
+
+	res = &memResult{fields: scanFields("a"), rows: nil}
+	if _, err = readOne[int](res, 0); err != NO_ROWS_ERROR {
+		t.Fatalf("Expected NO_ROWS_ERROR, got: %v", err)
+	}
+
+	res = &memResult{fields: scanFields("a"), rows: []Row{{int8(1)}, {int8(2)}}}
+	if _, err = readOne[int](res, -1); err != MULTIPLE_ROWS_ERROR {
+		t.Fatalf("Expected MULTIPLE_ROWS_ERROR, got: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package geom contains Go types for values of MySQL spatial (GEOMETRY)
+// columns.
+//
+// MySQL stores geometry values in its internal format: 4-byte SRID (little
+// endian) followed by the WKB representation of the geometry. Decode and
+// Encode convert between this format and Go values.
+//
+// Geometry types and Value implement driver.Valuer so they can be binded as
+// statement parameters. Their String methods return WKT, so in text queries
+// they can be used this way:
+//
+//	c.Query("INSERT zones VALUES (%d, ST_GeomFromText('%s', 4326))", id, zone)
+package geom
+
+import (
+	"database/sql/driver"
+	"strconv"
+)
+
+// Type of geometry as defined by WKB.
+type Type uint32
+
+const (
+	POINT              Type = 1
+	LINESTRING         Type = 2
+	POLYGON            Type = 3
+	MULTIPOINT         Type = 4
+	MULTILINESTRING    Type = 5
+	MULTIPOLYGON       Type = 6
+	GEOMETRYCOLLECTION Type = 7
+)
+
+var typeNames = [...]string{
+	POINT:              "POINT",
+	LINESTRING:         "LINESTRING",
+	POLYGON:            "POLYGON",
+	MULTIPOINT:         "MULTIPOINT",
+	MULTILINESTRING:    "MULTILINESTRING",
+	MULTIPOLYGON:       "MULTIPOLYGON",
+	GEOMETRYCOLLECTION: "GEOMETRYCOLLECTION",
+}
+
+func (t Type) String() string {
+	if t != 0 && int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return "GEOMETRY(" + strconv.FormatUint(uint64(t), 10) + ")"
+}
+
+// Geometry is implemented by Point, LineString, Polygon, MultiPoint,
+// MultiLineString, MultiPolygon and GeometryCollection.
+type Geometry interface {
+	Type() Type
+	// Appends WKB representation of geometry to buf (little endian).
+	appendWKB(buf []byte) []byte
+	// Appends WKT representation of geometry without type name to buf.
+	appendWKT(buf []byte) []byte
+}
+
+type Point struct {
+	X, Y float64
+}
+
+// Points of line string.
+type LineString []Point
+
+// Rings of polygon: the first one is the exterior ring, the rest are holes.
+// Every ring should be closed (its first and last points are equal).
+type Polygon []LineString
+
+type MultiPoint []Point
+
+type MultiLineString []LineString
+
+type MultiPolygon []Polygon
+
+type GeometryCollection []Geometry
+
+func (Point) Type() Type              { return POINT }
+func (LineString) Type() Type         { return LINESTRING }
+func (Polygon) Type() Type            { return POLYGON }
+func (MultiPoint) Type() Type         { return MULTIPOINT }
+func (MultiLineString) Type() Type    { return MULTILINESTRING }
+func (MultiPolygon) Type() Type       { return MULTIPOLYGON }
+func (GeometryCollection) Type() Type { return GEOMETRYCOLLECTION }
+
+// Returns WKT representation of g, eg: POLYGON((0 0,1 0,1 1,0 0)).
+func WKT(g Geometry) string {
+	return string(appendWKT(nil, g))
+}
+
+func appendWKT(buf []byte, g Geometry) []byte {
+	return g.appendWKT(append(buf, g.Type().String()...))
+}
+
+func (p Point) String() string              { return WKT(p) }
+func (l LineString) String() string         { return WKT(l) }
+func (p Polygon) String() string            { return WKT(p) }
+func (m MultiPoint) String() string         { return WKT(m) }
+func (m MultiLineString) String() string    { return WKT(m) }
+func (m MultiPolygon) String() string       { return WKT(m) }
+func (c GeometryCollection) String() string { return WKT(c) }
+
+// Implements driver.Valuer interface: returns geometry in MySQL internal
+// format with SRID 0. Use Value to bind geometry with other SRID.
+func (p Point) Value() (driver.Value, error)              { return Encode(p, 0), nil }
+func (l LineString) Value() (driver.Value, error)         { return Encode(l, 0), nil }
+func (p Polygon) Value() (driver.Value, error)            { return Encode(p, 0), nil }
+func (m MultiPoint) Value() (driver.Value, error)         { return Encode(m, 0), nil }
+func (m MultiLineString) Value() (driver.Value, error)    { return Encode(m, 0), nil }
+func (m MultiPolygon) Value() (driver.Value, error)       { return Encode(m, 0), nil }
+func (c GeometryCollection) Value() (driver.Value, error) { return Encode(c, 0), nil }
+
+func appendFloat(buf []byte, f float64) []byte {
+	return strconv.AppendFloat(buf, f, 'f', -1, 64)
+}
+
+func (p Point) appendCoords(buf []byte) []byte {
+	buf = appendFloat(buf, p.X)
+	buf = append(buf, ' ')
+	return appendFloat(buf, p.Y)
+}
+
+// Appends "(x y,x y,...)" or " EMPTY".
+func appendPoints(buf []byte, pts []Point, paren bool) []byte {
+	if len(pts) == 0 {
+		return append(buf, " EMPTY"...)
+	}
+	buf = append(buf, '(')
+	for ii, p := range pts {
+		if ii > 0 {
+			buf = append(buf, ',')
+		}
+		if paren {
+			buf = append(buf, '(')
+		}
+		buf = p.appendCoords(buf)
+		if paren {
+			buf = append(buf, ')')
+		}
+	}
+	return append(buf, ')')
+}
+
+func appendList[T any](buf []byte, list []T, elem func(T, []byte) []byte) []byte {
+	if len(list) == 0 {
+		return append(buf, " EMPTY"...)
+	}
+	buf = append(buf, '(')
+	for ii, e := range list {
+		if ii > 0 {
+			buf = append(buf, ',')
+		}
+		buf = elem(e, buf)
+	}
+	return append(buf, ')')
+}
+
+func (p Point) appendWKT(buf []byte) []byte {
+	return append(p.appendCoords(append(buf, '(')), ')')
+}
+
+func (l LineString) appendWKT(buf []byte) []byte {
+	return appendPoints(buf, l, false)
+}
+
+func (p Polygon) appendWKT(buf []byte) []byte {
+	return appendList(buf, p, LineString.appendWKT)
+}
+
+func (m MultiPoint) appendWKT(buf []byte) []byte {
+	return appendPoints(buf, m, true)
+}
+
+func (m MultiLineString) appendWKT(buf []byte) []byte {
+	return appendList(buf, m, LineString.appendWKT)
+}
+
+func (m MultiPolygon) appendWKT(buf []byte) []byte {
+	return appendList(buf, m, Polygon.appendWKT)
+}
+
+func (c GeometryCollection) appendWKT(buf []byte) []byte {
+	return appendList(buf, c, func(g Geometry, buf []byte) []byte {
+		return appendWKT(buf, g)
+	})
+}
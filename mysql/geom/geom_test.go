@@ -0,0 +1,135 @@
+package geom
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+var square = Polygon{{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}}}
+
+var geoms = []struct {
+	g   Geometry
+	wkt string
+}{
+	{Point{1.5, -2}, "POINT(1.5 -2)"},
+	{LineString{{0, 0}, {1, 1}}, "LINESTRING(0 0,1 1)"},
+	{
+		append(square, LineString{{1, 1}, {2, 1}, {2, 2}, {1, 1}}),
+		"POLYGON((0 0,4 0,4 4,0 4,0 0),(1 1,2 1,2 2,1 1))",
+	},
+	{MultiPoint{{0, 0}, {1e6, 0.25}}, "MULTIPOINT((0 0),(1000000 0.25))"},
+	{
+		MultiLineString{{{0, 0}, {1, 1}}, {{2, 2}, {3, 3}}},
+		"MULTILINESTRING((0 0,1 1),(2 2,3 3))",
+	},
+	{MultiPolygon{square}, "MULTIPOLYGON(((0 0,4 0,4 4,0 4,0 0)))"},
+	{
+		GeometryCollection{Point{1, 2}, GeometryCollection{}, square},
+		"GEOMETRYCOLLECTION(POINT(1 2),GEOMETRYCOLLECTION EMPTY," +
+			"POLYGON((0 0,4 0,4 4,0 4,0 0)))",
+	},
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, ex := range geoms {
+		b := Encode(ex.g, 4326)
+		v, err := Decode(b)
+		if err != nil {
+			t.Fatalf("%s: %v", ex.wkt, err)
+		}
+		if v.SRID != 4326 || !reflect.DeepEqual(v.Geometry, ex.g) {
+			t.Fatalf("%s: decoded %d %#v", ex.wkt, v.SRID, v.Geometry)
+		}
+		if s := v.String(); s != ex.wkt {
+			t.Fatalf("Bad WKT: %s != %s", s, ex.wkt)
+		}
+		// Truncated data
+		for n := 0; n < len(b); n += 3 {
+			if _, err = Decode(b[:n]); err == nil {
+				t.Fatalf("%s: no error for %d bytes", ex.wkt, n)
+			}
+		}
+	}
+}
+
+func TestDecodeMySQL(t *testing.T) {
+	// SELECT ST_GeomFromText('POINT(1 2)', 4326)
+	b, _ := hex.DecodeString(
+		"E6100000" + "0101000000" + "000000000000F03F" + "0000000000000040",
+	)
+	v, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.SRID != 4326 || v.Geometry != (Point{1, 2}) {
+		t.Fatalf("Bad value: %d %v", v.SRID, v)
+	}
+	if !bytes.Equal(Encode(v.Geometry, v.SRID), b) {
+		t.Fatal("Encoded value differs")
+	}
+
+	// Big endian WKB
+	b, _ = hex.DecodeString(
+		"000000000400000001" + "000000000100000000000000003FF0000000000000",
+	)
+	g, err := DecodeWKB(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(g, MultiPoint{{0, 1}}) {
+		t.Fatalf("Bad geometry: %v", g)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, s := range []string{
+		// Bad byte order
+		"02010000000000000000000000000000000000000000",
+		// Unknown type
+		"0108000000",
+		// Too many points
+		"0102000000FFFFFFFF",
+		// Line string in multipoint
+		"01040000000100000001020000000000000000000000",
+		// Trailing byte
+		"010100000000000000000000000000000000000000000000FF",
+	} {
+		b, _ := hex.DecodeString(s)
+		if _, err := DecodeWKB(b); err == nil {
+			t.Fatalf("No error for %s", s)
+		}
+	}
+	// Too deeply nested collections
+	var b []byte
+	for ii := 0; ii <= maxDepth; ii++ {
+		b = append(b, 1, 7, 0, 0, 0, 1, 0, 0, 0)
+	}
+	b = append(b, WKB(Point{})...)
+	if _, err := DecodeWKB(b); err != WKB_ERROR {
+		t.Fatal("Expected WKB_ERROR, got:", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	v := Value{4326, Point{1, 2}}
+	b, err := v.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s Value
+	if err = s.Scan(b); err != nil || s != v {
+		t.Fatalf("Scan: %v %v", s, err)
+	}
+	if err = s.Scan(nil); err != nil || s.Geometry != nil {
+		t.Fatalf("Scan NULL: %v %v", s, err)
+	}
+	if b, err = s.Value(); b != nil || err != nil {
+		t.Fatalf("NULL Value: %v %v", b, err)
+	}
+	if b, _ = (LineString{{1, 2}}).Value(); !bytes.Equal(b.([]byte),
+		Encode(LineString{{1, 2}}, 0)) {
+		t.Fatal("Bad LineString value")
+	}
+}
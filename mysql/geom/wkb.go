@@ -0,0 +1,311 @@
+package geom
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	WKB_ERROR      = errors.New("geom: invalid WKB data")
+	WKB_TYPE_ERROR = errors.New("geom: unknown WKB geometry type")
+)
+
+// Value of GEOMETRY column: geometry with its spatial reference system
+// identifier. Zero Value (nil Geometry) represents NULL.
+type Value struct {
+	SRID uint32
+	Geometry
+}
+
+// Returns WKT representation of geometry (without SRID) or "NULL".
+func (v Value) String() string {
+	if v.Geometry == nil {
+		return "NULL"
+	}
+	return WKT(v.Geometry)
+}
+
+// Implements driver.Valuer interface.
+func (v Value) Value() (driver.Value, error) {
+	if v.Geometry == nil {
+		return nil, nil
+	}
+	return Encode(v.Geometry, v.SRID), nil
+}
+
+// Implements sql.Scanner interface.
+func (v *Value) Scan(src interface{}) (err error) {
+	switch b := src.(type) {
+	case nil:
+		*v = Value{}
+	case []byte:
+		*v, err = Decode(b)
+	default:
+		err = fmt.Errorf("geom: can't scan %T into Value", src)
+	}
+	return
+}
+
+// Encodes g into MySQL internal format.
+func Encode(g Geometry, srid uint32) []byte {
+	buf := binary.LittleEndian.AppendUint32(make([]byte, 0, 64), srid)
+	return appendWKB(buf, g)
+}
+
+// Decodes value of GEOMETRY column (MySQL internal format).
+func Decode(b []byte) (Value, error) {
+	if len(b) < 4 {
+		return Value{}, WKB_ERROR
+	}
+	g, err := DecodeWKB(b[4:])
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{binary.LittleEndian.Uint32(b), g}, nil
+}
+
+// Returns WKB representation of g (little endian).
+func WKB(g Geometry) []byte {
+	return appendWKB(nil, g)
+}
+
+// Decodes WKB representation of geometry.
+func DecodeWKB(b []byte) (Geometry, error) {
+	d := decoder{buf: b}
+	g, err := d.geometry(0)
+	if err == nil && len(d.buf) != 0 {
+		err = WKB_ERROR
+	}
+	return g, err
+}
+
+func appendWKB(buf []byte, g Geometry) []byte {
+	buf = append(buf, 1) // little endian
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(g.Type()))
+	return g.appendWKB(buf)
+}
+
+func appendCount(buf []byte, n int) []byte {
+	return binary.LittleEndian.AppendUint32(buf, uint32(n))
+}
+
+func (p Point) appendWKB(buf []byte) []byte {
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.X))
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(p.Y))
+}
+
+func (l LineString) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(l))
+	for _, p := range l {
+		buf = p.appendWKB(buf)
+	}
+	return buf
+}
+
+func (p Polygon) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(p))
+	for _, r := range p {
+		buf = r.appendWKB(buf)
+	}
+	return buf
+}
+
+func (m MultiPoint) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(m))
+	for _, p := range m {
+		buf = appendWKB(buf, p)
+	}
+	return buf
+}
+
+func (m MultiLineString) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(m))
+	for _, l := range m {
+		buf = appendWKB(buf, l)
+	}
+	return buf
+}
+
+func (m MultiPolygon) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(m))
+	for _, p := range m {
+		buf = appendWKB(buf, p)
+	}
+	return buf
+}
+
+func (c GeometryCollection) appendWKB(buf []byte) []byte {
+	buf = appendCount(buf, len(c))
+	for _, g := range c {
+		buf = appendWKB(buf, g)
+	}
+	return buf
+}
+
+// Nesting limit for geometry collections.
+const maxDepth = 32
+
+type decoder struct {
+	buf []byte
+	bo  binary.ByteOrder
+}
+
+func (d *decoder) uint32() (uint32, error) {
+	if len(d.buf) < 4 {
+		return 0, WKB_ERROR
+	}
+	u := d.bo.Uint32(d.buf)
+	d.buf = d.buf[4:]
+	return u, nil
+}
+
+// Reads number of elements that have at least min bytes each.
+func (d *decoder) count(min int) (int, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(min) > uint64(len(d.buf)) {
+		return 0, WKB_ERROR
+	}
+	return int(n), nil
+}
+
+func (d *decoder) point() (Point, error) {
+	if len(d.buf) < 16 {
+		return Point{}, WKB_ERROR
+	}
+	p := Point{
+		math.Float64frombits(d.bo.Uint64(d.buf)),
+		math.Float64frombits(d.bo.Uint64(d.buf[8:])),
+	}
+	d.buf = d.buf[16:]
+	return p, nil
+}
+
+func (d *decoder) lineString() (LineString, error) {
+	n, err := d.count(16)
+	if err != nil {
+		return nil, err
+	}
+	l := make(LineString, n)
+	for ii := range l {
+		l[ii], _ = d.point()
+	}
+	return l, nil
+}
+
+func (d *decoder) polygon() (Polygon, error) {
+	n, err := d.count(4)
+	if err != nil {
+		return nil, err
+	}
+	p := make(Polygon, n)
+	for ii := range p {
+		if p[ii], err = d.lineString(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Reads geometry with its header. If typ != 0 geometry has to be of this
+// type.
+func (d *decoder) header(typ Type) (Type, error) {
+	if len(d.buf) < 5 {
+		return 0, WKB_ERROR
+	}
+	switch d.buf[0] {
+	case 0:
+		d.bo = binary.BigEndian
+	case 1:
+		d.bo = binary.LittleEndian
+	default:
+		return 0, WKB_ERROR
+	}
+	d.buf = d.buf[1:]
+	t, _ := d.uint32()
+	if typ != 0 && Type(t) != typ {
+		return 0, WKB_ERROR
+	}
+	return Type(t), nil
+}
+
+func (d *decoder) geometry(depth int) (Geometry, error) {
+	typ, err := d.header(0)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case POINT:
+		return d.point()
+	case LINESTRING:
+		return d.lineString()
+	case POLYGON:
+		return d.polygon()
+	case MULTIPOINT:
+		n, err := d.count(21)
+		if err != nil {
+			return nil, err
+		}
+		m := make(MultiPoint, n)
+		for ii := range m {
+			if _, err = d.header(POINT); err != nil {
+				return nil, err
+			}
+			if m[ii], err = d.point(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case MULTILINESTRING:
+		n, err := d.count(9)
+		if err != nil {
+			return nil, err
+		}
+		m := make(MultiLineString, n)
+		for ii := range m {
+			if _, err = d.header(LINESTRING); err != nil {
+				return nil, err
+			}
+			if m[ii], err = d.lineString(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case MULTIPOLYGON:
+		n, err := d.count(9)
+		if err != nil {
+			return nil, err
+		}
+		m := make(MultiPolygon, n)
+		for ii := range m {
+			if _, err = d.header(POLYGON); err != nil {
+				return nil, err
+			}
+			if m[ii], err = d.polygon(); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case GEOMETRYCOLLECTION:
+		if depth == maxDepth {
+			return nil, WKB_ERROR
+		}
+		n, err := d.count(5)
+		if err != nil {
+			return nil, err
+		}
+		c := make(GeometryCollection, n)
+		for ii := range c {
+			if c[ii], err = d.geometry(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return c, nil
+	}
+	return nil, WKB_TYPE_ERROR
+}
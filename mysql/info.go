@@ -0,0 +1,47 @@
+package mysql
+
+import (
+	"strconv"
+)
+
+// Information about the result of the command returned by the server in an
+// OK packet, e.g: "Records: 3  Duplicates: 1  Warnings: 0" for multi-row
+// INSERT or "Rows matched: 5  Changed: 2  Warnings: 0" for UPDATE. Fields
+// not present in the message are zero.
+type Info struct {
+	Records     uint64 // INSERT ... VALUES (...),(...), LOAD DATA, ALTER TABLE
+	Duplicates  uint64 // INSERT, ALTER TABLE
+	Deleted     uint64 // LOAD DATA
+	Skipped     uint64 // LOAD DATA
+	RowsMatched uint64 // UPDATE
+	RowsChanged uint64 // UPDATE
+	Warnings    uint64
+}
+
+// Parses Result.Message into Info structure.
+func ParseInfo(msg string) (info Info, err error) {
+	err = parseItems(msg, func(k, v string) (err error) {
+		var p *uint64
+		switch k {
+		case "Records":
+			p = &info.Records
+		case "Duplicates":
+			p = &info.Duplicates
+		case "Deleted":
+			p = &info.Deleted
+		case "Skipped":
+			p = &info.Skipped
+		case "Rows matched":
+			p = &info.RowsMatched
+		case "Changed":
+			p = &info.RowsChanged
+		case "Warnings":
+			p = &info.Warnings
+		default:
+			return
+		}
+		*p, err = strconv.ParseUint(v, 10, 64)
+		return
+	})
+	return
+}
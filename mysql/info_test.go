@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestParseInfo(t *testing.T) {
+	tests := []struct {
+		msg string
+		exp Info
+	}{
+		{"", Info{}},
+		{"Records: 3  Duplicates: 1  Warnings: 0",
+			Info{Records: 3, Duplicates: 1}},
+		{"Rows matched: 5  Changed: 2  Warnings: 1",
+			Info{RowsMatched: 5, RowsChanged: 2, Warnings: 1}},
+		{"Records: 4  Deleted: 0  Skipped: 2  Warnings: 2",
+			Info{Records: 4, Skipped: 2, Warnings: 2}},
+	}
+	for _, tt := range tests {
+		info, err := ParseInfo(tt.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info != tt.exp {
+			t.Errorf("ParseInfo(%q): ret=%+v exp=%+v", tt.msg, info, tt.exp)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 // MySQL Client API written entirely in Go without any external dependences.
 package mysql
 
+import (
+	"io"
+	"iter"
+)
+
 type ConnCommon interface {
 	Start(sql string, params ...interface{}) (Result, error)
 	Prepare(sql string) (Stmt, error)
+	PrepareCached(sql string) (Stmt, error)
+
+	Pipeline() Pipeline
 
 	Ping() error
 	ThreadId() uint32
@@ -23,10 +31,27 @@ type Conn interface {
 	IsConnected() bool
 	Reconnect() error
 	Use(dbname string) error
+	ChangeUser(user, passwd, db string) error
+	ResetSession() error
 	Register(sql string)
 	SetMaxPktSize(new_size int) int
+	SetConnAttr(name, value string)
+	SetMultiStatements(on bool) error
+	SetAutoWarnings(on bool)
+	SetTypedText(on bool)
+	SetSharedRowBuffer(on bool)
+	SetStrictWarnings(codes ...uint16)
+	SetStmtCacheSize(size int) error
+	StmtCacheStats() StmtCacheStats
 
 	Begin() (Transaction, error)
+
+	Statistics() (Statistics, error)
+	ProcessList() ([]Row, Result, error)
+	Kill(thr_id uint32) error
+	Refresh(flags byte) error
+	Shutdown() error
+	DumpDebugInfo() error
 }
 
 type Transaction interface {
@@ -40,6 +65,7 @@ type Transaction interface {
 
 type Stmt interface {
 	Bind(params ...interface{})
+	BindNamed(params map[string]interface{}) error
 	ResetParams()
 	Run(params ...interface{}) (Result, error)
 	Delete() error
@@ -54,28 +80,89 @@ type Stmt interface {
 	Exec(params ...interface{}) ([]Row, Result, error)
 	ExecFirst(params ...interface{}) (Row, Result, error)
 	ExecLast(params ...interface{}) (Row, Result, error)
+	ExecCall(params ...interface{}) (*CallResult, error)
 }
 
 type Result interface {
 	StatusOnly() bool
 	ScanRow(Row) error
+	ScanStream() (RowStream, error)
+	ScanInto(dest ...interface{}) error
 	GetRow() (Row, error)
 
 	MoreResults() bool
 	NextResult() (Result, error)
+	All() iter.Seq2[Row, error]
+	Results() iter.Seq2[Result, error]
 
 	Fields() []*Field
 	Map(string) int
 	Message() string
+	Info() (Info, error)
 	AffectedRows() uint64
 	InsertId() uint64
 	WarnCount() int
+	Warnings() []Warning
+
+	InTransaction() bool
+	Autocommit() bool
+	NoIndexUsed() bool
+	NoGoodIndexUsed() bool
+	CursorExists() bool
+	IsOutParams() bool
 
 	MakeRow() Row
+	ScanStruct(row Row, dst interface{}) error
 	GetRows() ([]Row, error)
 	End() error
 	GetFirstRow() (Row, error)
 	GetLastRow() (Row, error)
 }
 
+// Queue of commands that are sent to the server together, without waiting for
+// responses (see Run). It saves one round trip per command, so use it for
+// batches of independent statements.
+type Pipeline interface {
+	// Queues text query. If you specify the parameters, the SQL string will
+	// be a result of fmt.Sprintf(sql, params...).
+	Query(sql string, params ...interface{})
+	// Queues execution of prepared statement (obtained from the same
+	// connection) with params. Parameters are binded when Run is called.
+	Exec(st Stmt, params ...interface{})
+	// Returns the number of queued commands.
+	Len() int
+	// Sends all queued commands, reads their responses in order and clears
+	// the queue. Returns one result for every command. An error is returned
+	// only if the connection can't be used any more.
+	Run() ([]PipelineResult, error)
+}
+
+// Result of one pipelined command: rows of its (first) result, the result
+// and the error reported for the command.
+type PipelineResult struct {
+	Rows []Row
+	Res  Result
+	Err  error
+}
+
 var New func(proto, laddr, raddr, user, passwd string, db ...string) Conn
+
+// Row that column values are read one by one, directly from the connection
+// (see Result.ScanStream). Value and Reader return io.EOF if there are no
+// more columns in the row.
+type RowStream interface {
+	// Returns the number of the column that will be read by next Value or
+	// Reader call.
+	Column() int
+	// Reads the value of the next column (the same value as ScanRow stores
+	// in Row).
+	Value() (interface{}, error)
+	// Returns the reader for the value of the next column and the length of
+	// the value. Returns nil reader for NULL. It works for all columns of
+	// text results and for string, blob and decimal columns of binary
+	// results. The reader is valid until next Value, Reader or Close call.
+	Reader() (io.Reader, int64, error)
+	// Discards unread columns. Must be called before next ScanRow or
+	// ScanStream call.
+	Close() error
+}
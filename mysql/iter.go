@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// Returned by ScanRow if it is called after it returned io.EOF.
+var READ_AFTER_EOR_ERROR = errors.New("previous GetRow call returned nil row")
+
+// Reads and discards all unreaded rows of r and all next results.
+func discardAll(r Result) error {
+	for r != nil {
+		if err := r.End(); err != nil && err != READ_AFTER_EOR_ERROR {
+			return err
+		}
+		var err error
+		if r, err = r.NextResult(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns iterator over rows of r. Every row is a new slice, so you can
+// retain it (unless the connection shares its row buffer, see Row). If an
+// error occurs it is yielded with nil row and the iteration stops. If you
+// break the loop, remaining rows of r and all next results are read and
+// discarded, so you can send next command to the server.
+//
+// Example:
+//
+//	for row, err := range res.All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(row.Str(0))
+//	}
+func All(r Result) iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		for {
+			row := r.MakeRow()
+			err := r.ScanRow(row)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(row, nil) {
+				discardAll(r)
+				return
+			}
+		}
+	}
+}
+
+// Returns iterator over r and all next results (see NextResult). Unreaded
+// rows of the yielded result are discarded before the next result is
+// obtained. If you break the loop all remaining rows and results are
+// discarded.
+func Results(r Result) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		for r != nil {
+			if !yield(r, nil) {
+				discardAll(r)
+				return
+			}
+			err := r.End()
+			if err != nil && err != READ_AFTER_EOR_ERROR {
+				yield(nil, err)
+				return
+			}
+			if r, err = r.NextResult(); err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
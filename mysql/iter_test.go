@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+)
+
+// memResult with next results
+type multiResult struct {
+	memResult
+	next *multiResult
+}
+
+func (r *multiResult) MoreResults() bool { return r.next != nil }
+
+func (r *multiResult) NextResult() (Result, error) {
+	if r.next == nil {
+		return nil, nil
+	}
+	return r.next, nil
+}
+
+func newMultiResult(rows ...[]Row) *multiResult {
+	var r *multiResult
+	for ii := len(rows) - 1; ii >= 0; ii-- {
+		r = &multiResult{
+			memResult: memResult{fields: scanFields("a"), rows: rows[ii]},
+			next:      r,
+		}
+	}
+	return r
+}
+
+func TestAll(t *testing.T) {
+	res := newMultiResult([]Row{{"a"}, {"b"}, {"c"}})
+	var got []Row
+	for row, err := range All(res) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, row)
+	}
+	// Every row is a new slice
+	if len(got) != 3 || got[0].Str(0) != "a" || got[1].Str(0) != "b" ||
+		got[2].Str(0) != "c" {
+		t.Fatalf("Bad rows: %v", got)
+	}
+
+	// Break should discard all remaining rows and results
+	res = newMultiResult([]Row{{"a"}, {"b"}}, []Row{{"c"}})
+	for range All(res) {
+		break
+	}
+	if len(res.rows) != 0 || len(res.next.rows) != 0 {
+		t.Fatal("Rows not discarded after break")
+	}
+}
+
+type errResult struct {
+	memResult
+}
+
+var testErr = errors.New("test error")
+
+func (r *errResult) ScanRow(row Row) error { return testErr }
+
+func TestAllError(t *testing.T) {
+	n := 0
+	for row, err := range All(&errResult{memResult{fields: scanFields("a")}}) {
+		n++
+		if err != testErr || row != nil {
+			t.Fatalf("Unexpected row=%v err=%v", row, err)
+		}
+	}
+	if n != 1 {
+		t.Fatalf("Error yielded %d times", n)
+	}
+}
+
+func TestResults(t *testing.T) {
+	res := newMultiResult([]Row{{"a"}}, []Row{{"b"}, {"c"}}, nil)
+	n := 0
+	for r, err := range Results(res) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Don't read rows of the second result
+		if n != 1 {
+			for _, err := range All(r) {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		n++
+	}
+	if n != 3 {
+		t.Fatalf("Bad number of results: %d", n)
+	}
+
+	res = newMultiResult([]Row{{"a"}}, []Row{{"b"}})
+	for range Results(res) {
+		break
+	}
+	if len(res.rows) != 0 || len(res.next.rows) != 0 {
+		t.Fatal("Rows not discarded after break")
+	}
+}
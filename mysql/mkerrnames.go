@@ -0,0 +1,50 @@
+//go:build ignore
+
+// Generates errnames.go from the list of MySQL error codes in errors.go.
+// Run it using: go generate
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+)
+
+var constRe = regexp.MustCompile(`^\s+((?:ER|WARN)_\w+)\s+=\s+(\d+)`)
+
+func main() {
+	f, err := os.Open("errors.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by mkerrnames.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package mysql\n\n")
+	buf.WriteString("// Maps MySQL error codes to their symbolic names\n")
+	buf.WriteString("var errorNames = map[uint16]string{\n")
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if m := constRe.FindStringSubmatch(sc.Text()); m != nil {
+			fmt.Fprintf(&buf, "\t%s: %q,\n", m[2], m[1])
+		}
+	}
+	if err = sc.Err(); err != nil {
+		log.Fatal(err)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = ioutil.WriteFile("errnames.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
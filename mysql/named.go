@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var MIXED_PARAMS_ERROR = errors.New(
+	"mysql: named and positional parameters in one statement",
+)
+
+func isNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9'
+}
+
+// Returns index of the first byte after the quoted string/identifier that
+// starts at sql[ii].
+func skipQuoted(sql string, ii int) int {
+	q := sql[ii]
+	for ii++; ii < len(sql); ii++ {
+		switch sql[ii] {
+		case '\\':
+			if q != '`' {
+				ii++
+			}
+		case q:
+			if ii+1 < len(sql) && sql[ii+1] == q {
+				// Doubled quote
+				ii++
+				continue
+			}
+			return ii + 1
+		}
+	}
+	return ii
+}
+
+// Returns index of the first byte after the comment that starts at sql[ii]
+// or ii if there is no comment at sql[ii].
+func skipComment(sql string, ii int) int {
+	switch {
+	case sql[ii] == '#',
+		strings.HasPrefix(sql[ii:], "--") &&
+			(ii+2 == len(sql) || sql[ii+2] <= ' '):
+		if n := strings.IndexByte(sql[ii:], '\n'); n != -1 {
+			return ii + n + 1
+		}
+		return len(sql)
+	case strings.HasPrefix(sql[ii:], "/*"):
+		if n := strings.Index(sql[ii+2:], "*/"); n != -1 {
+			return ii + 2 + n + 2
+		}
+		return len(sql)
+	}
+	return ii
+}
+
+// Rewrites named parameters (:name) in sql to ? markers. Returns rewritten
+// statement and names of parameters in the order of markers (the same name
+// may occur many times). Quoted strings, quoted identifiers, comments and
+// variables (@name, @@name) are left untouched.
+//
+// If sql contains any ? marker, only positional parameters can be used:
+// :name causes MIXED_PARAMS_ERROR.
+//
+// If sql doesn't contain named parameters it is returned unchanged with nil
+// names.
+func ParseNamed(sql string) (string, []string, error) {
+	var (
+		names      []string
+		positional bool
+		buf        []byte
+		last       int // First byte of sql not copied to buf
+	)
+	for ii := 0; ii < len(sql); {
+		switch sql[ii] {
+		case '\'', '"', '`':
+			ii = skipQuoted(sql, ii)
+			continue
+		case '?':
+			positional = true
+		case '#', '-', '/':
+			if n := skipComment(sql, ii); n != ii {
+				ii = n
+				continue
+			}
+		case '@':
+			// User or system variable
+			ii++
+			for ii < len(sql) && (isNameChar(sql[ii]) || sql[ii] == '@' ||
+				sql[ii] == '.' || sql[ii] == '$') {
+				ii++
+			}
+			continue
+		case ':':
+			if ii+1 == len(sql) || !isNameStart(sql[ii+1]) ||
+				ii > 0 && sql[ii-1] == ':' {
+				break
+			}
+			end := ii + 2
+			for end < len(sql) && isNameChar(sql[end]) {
+				end++
+			}
+			names = append(names, sql[ii+1:end])
+			buf = append(append(buf, sql[last:ii]...), '?')
+			last = end
+			ii = end
+			continue
+		}
+		ii++
+	}
+	if names == nil {
+		return sql, nil, nil
+	}
+	if positional {
+		return "", nil, MIXED_PARAMS_ERROR
+	}
+	return string(append(buf, sql[last:]...)), names, nil
+}
+
+// Returns index sequences (see reflect.Value.FieldByIndex) of the fields of
+// struct type t by lowercased names. Names are obtained using the same rules
+// as in ScanStruct.
+func FieldIndexes(t reflect.Type) map[string][]int {
+	plan := getPlan(t)
+	m := make(map[string][]int, len(plan))
+	for name, fp := range plan {
+		m[name] = fp.index
+	}
+	return m
+}
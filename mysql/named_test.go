@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+)
+
+var parseNamedTests = []struct {
+	sql, out string
+	names    []string
+	err      error
+}{
+	{"select 1", "select 1", nil, nil},
+	{"select * from t where id=?", "select * from t where id=?", nil, nil},
+	{
+		"insert t values (:id, :name, :id)",
+		"insert t values (?, ?, ?)",
+		[]string{"id", "name", "id"}, nil,
+	},
+	{
+		"update t set a=:a_1 where b=:b",
+		"update t set a=? where b=?",
+		[]string{"a_1", "b"}, nil,
+	},
+	{
+		"select ':x', \"@y\", `:z`, 'it''s :a', 'a\\':b' from t where c=:c",
+		"select ':x', \"@y\", `:z`, 'it''s :a', 'a\\':b' from t where c=?",
+		[]string{"c"}, nil,
+	},
+	{
+		"select a -- :x\nfrom t # @y\nwhere /* :z */ b=:b",
+		"select a -- :x\nfrom t # @y\nwhere /* :z */ b=?",
+		[]string{"b"}, nil,
+	},
+	{
+		"select @@session.sql_mode, @v := :v, a-1, @`u`, @v:=@w",
+		"select @@session.sql_mode, @v := ?, a-1, @`u`, @v:=@w",
+		[]string{"v"}, nil,
+	},
+	// User variables are never parameters
+	{"select @a from t where id=?", "select @a from t where id=?", nil, nil},
+	{"SET @x = 1", "SET @x = 1", nil, nil},
+	{"select @a", "select @a", nil, nil},
+	{"CALL p(@out)", "CALL p(@out)", nil, nil},
+	{"CALL p(:in, @out)", "CALL p(?, @out)", []string{"in"}, nil},
+	{"select :a, ?", "", nil, MIXED_PARAMS_ERROR},
+	{"select a::b, 1:2", "select a::b, 1:2", nil, nil},
+}
+
+func TestParseNamed(t *testing.T) {
+	for _, tt := range parseNamedTests {
+		out, names, err := ParseNamed(tt.sql)
+		if err != tt.err {
+			t.Errorf("%q: err=%v exp=%v", tt.sql, err, tt.err)
+			continue
+		}
+		if out != tt.out || !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("%q:\n ret=%q %q\n exp=%q %q",
+				tt.sql, out, names, tt.out, tt.names)
+		}
+	}
+}
+
+func TestFieldIndexes(t *testing.T) {
+	type Base struct {
+		Id int
+	}
+	type S struct {
+		Base
+		Name  string `mysql:"user_name"`
+		Skip  int    `mysql:"-"`
+		other int
+	}
+	ret := FieldIndexes(reflect.TypeOf(S{}))
+	exp := map[string][]int{"id": {0, 0}, "user_name": {1}}
+	if !reflect.DeepEqual(ret, exp) {
+		t.Fatalf("ret=%v exp=%v", ret, exp)
+	}
+}
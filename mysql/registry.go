@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Converts a value of registered type to the value that engine can send to
+// the server (eg. nil, intX, uintX, floatX, bool, string, []byte, time.Time,
+// Date, Raw).
+type EncodeFunc func(val interface{}) (interface{}, error)
+
+// Decodes src (value from Row) into dst that is a pointer to registered type.
+type DecodeFunc func(dst interface{}, src interface{}) error
+
+var registry = struct {
+	sync.RWMutex
+	enc map[reflect.Type]EncodeFunc
+	dec map[reflect.Type]DecodeFunc
+}{
+	enc: make(map[reflect.Type]EncodeFunc),
+	dec: make(map[reflect.Type]DecodeFunc),
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// Registers encoder for values of type T. Registered encoder takes precedence
+// over driver.Valuer implementation and over default encoding of T. Types
+// should be registered before they are used (eg. in init function).
+func RegisterEncoder[T any](enc func(val T) (interface{}, error)) {
+	registry.Lock()
+	registry.enc[typeOf[T]()] = func(val interface{}) (interface{}, error) {
+		return enc(val.(T))
+	}
+	registry.Unlock()
+}
+
+// Registers decoder for values of type T. Registered decoder takes precedence
+// over sql.Scanner implementation and over default decoding of T. Types
+// should be registered before they are used (eg. in init function).
+func RegisterDecoder[T any](dec func(dst *T, src interface{}) error) {
+	registry.Lock()
+	registry.dec[typeOf[T]()] = func(dst interface{}, src interface{}) error {
+		return dec(dst.(*T), src)
+	}
+	registry.Unlock()
+}
+
+// Returns encoder registered for type t or nil.
+func GetEncoder(t reflect.Type) EncodeFunc {
+	registry.RLock()
+	enc := registry.enc[t]
+	registry.RUnlock()
+	return enc
+}
+
+// Returns decoder registered for type t or nil.
+func GetDecoder(t reflect.Type) DecodeFunc {
+	registry.RLock()
+	dec := registry.dec[t]
+	registry.RUnlock()
+	return dec
+}
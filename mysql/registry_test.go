@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testUUID [4]byte
+
+func init() {
+	RegisterDecoder(func(dst *testUUID, src interface{}) error {
+		b, ok := src.([]byte)
+		if !ok || len(b) != len(dst) {
+			return fmt.Errorf("can't decode %v as UUID", src)
+		}
+		copy(dst[:], b)
+		return nil
+	})
+	RegisterEncoder(func(u testUUID) (interface{}, error) {
+		return u[:], nil
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	enc := GetEncoder(reflect.TypeOf(testUUID{}))
+	if enc == nil {
+		t.Fatal("Encoder not registered")
+	}
+	if v, err := enc(testUUID{1, 2, 3, 4}); err != nil ||
+		!reflect.DeepEqual(v, []byte{1, 2, 3, 4}) {
+		t.Fatalf("Bad encoding: %v, %v", v, err)
+	}
+	if GetDecoder(reflect.TypeOf(0)) != nil {
+		t.Fatal("Unexpected decoder for int")
+	}
+
+	var s struct {
+		Id  testUUID
+		Opt *testUUID
+	}
+	err := ScanStruct(
+		&memResult{fields: scanFields("id", "opt")},
+		Row{[]byte("abcd"), nil}, &s,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Id != (testUUID{'a', 'b', 'c', 'd'}) || s.Opt != nil {
+		t.Fatalf("Bad decoding: %+v", s)
+	}
+	err = ScanStruct(
+		&memResult{fields: scanFields("id")}, Row{[]byte("abc")}, &s,
+	)
+	if err == nil || !strings.Contains(err.Error(), "UUID") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
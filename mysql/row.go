@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"github.com/ziutek/mymysql/mysql/geom"
 	"math"
 	"os"
 	"reflect"
@@ -18,8 +19,43 @@ import (
 //
 // If it is result of prepared statement execution, its element field can be:
 // intX, uintX, floatX, []byte, Date, Time, time.Time (in Local location) or nil
+//
+// If the connection shares its row buffer (see SetSharedRowBuffer), []byte
+// values of a row filled by ScanRow refer to the connection buffer, so they
+// are valid only until the next ScanRow (or other read from the same
+// connection). Use Clone to retain such row.
 type Row []interface{}
 
+// Makes []byte values of the row independent of the connection buffer (see
+// SetSharedRowBuffer). All of them are copied into one new allocation.
+func (tr Row) detach() {
+	n := 0
+	for _, v := range tr {
+		if b, ok := v.([]byte); ok {
+			n += len(b)
+		}
+	}
+	buf := make([]byte, 0, n)
+	for ii, v := range tr {
+		if b, ok := v.([]byte); ok {
+			nn := len(buf)
+			buf = append(buf, b...)
+			tr[ii] = buf[nn:len(buf):len(buf)]
+		}
+	}
+}
+
+// Returns a copy of the row that can be retained after next ScanRow.
+func (tr Row) Clone() Row {
+	if tr == nil {
+		return nil
+	}
+	c := make(Row, len(tr))
+	copy(c, tr)
+	c.detach()
+	return c
+}
+
 // Get the nn-th value and return it as []byte ([]byte{} if NULL)
 func (tr Row) Bin(nn int) (bin []byte) {
 	switch data := tr[nn].(type) {
@@ -472,3 +508,87 @@ func (tr Row) ForceFloat(nn int) (val float64) {
 	val, _ = tr.FloatErr(nn)
 	return
 }
+
+// Get the nn-th value of BIT column and return it as Bit (0 if NULL). Return
+// error if conversion is impossible.
+func (tr Row) BitErr(nn int) (val Bit, err error) {
+	switch data := tr[nn].(type) {
+	case nil:
+		// nop
+	case []byte:
+		val, err = DecodeBit(data)
+	case Bit:
+		val = data
+	default:
+		var u uint64
+		u, err = tr.Uint64Err(nn)
+		val = Bit(u)
+	}
+	return
+}
+
+// It is like BitErr but panics if conversion is impossible.
+func (tr Row) Bit(nn int) (val Bit) {
+	val, err := tr.BitErr(nn)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Get the nn-th value of SET column and return its members (nil if NULL).
+func (tr Row) Set(nn int) []string {
+	if tr[nn] == nil {
+		return nil
+	}
+	return splitSet(tr.Str(nn))
+}
+
+// Get the nn-th value of YEAR column and return it as int (0 if NULL). Binary
+// protocol returns YEAR as int16 (uint16 if UNSIGNED), text protocol as
+// decimal number. Return error if conversion is impossible or value is out of
+// YEAR range (0, 1901-2155).
+func (tr Row) YearErr(nn int) (val int, err error) {
+	y, err := tr.Int64Err(nn)
+	if err != nil {
+		return
+	}
+	if y != 0 && (y < 1901 || y > 2155) {
+		return 0, strconv.ErrRange
+	}
+	return int(y), nil
+}
+
+// It is like YearErr but panics if conversion is impossible.
+func (tr Row) Year(nn int) (val int) {
+	val, err := tr.YearErr(nn)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Get the nn-th value of GEOMETRY column and return it as geom.Value (zero
+// Value if NULL). Return error if conversion is impossible.
+func (tr Row) GeometryErr(nn int) (val geom.Value, err error) {
+	switch data := tr[nn].(type) {
+	case nil:
+		// nop
+	case []byte:
+		val, err = geom.Decode(data)
+	case geom.Value:
+		val = data
+	default:
+		err = os.ErrInvalid
+	}
+	return
+}
+
+// It is like GeometryErr but panics if conversion is impossible.
+func (tr Row) Geometry(nn int) (val geom.Value) {
+	val, err := tr.GeometryErr(nn)
+	if err != nil {
+		panic(err)
+	}
+	return
+}
@@ -0,0 +1,359 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	timestampType = reflect.TypeOf(Timestamp{})
+	dateType      = reflect.TypeOf(Date{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	scannerType   = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// Sets v (addressable value of struct field) using nn-th column of row
+type fieldSetter func(v reflect.Value, row Row, nn int) error
+
+type fieldPlan struct {
+	name  string // Go name of the field (for error messages)
+	index []int  // Index sequence for reflect.Value.FieldByIndex
+	set   fieldSetter
+}
+
+// Maps lowercased column names to struct fields
+type structPlan map[string]*fieldPlan
+
+var plans = struct {
+	sync.RWMutex
+	m map[reflect.Type]structPlan
+}{m: make(map[reflect.Type]structPlan)}
+
+// Returns plan for struct type t. Plans are created once for every type.
+func getPlan(t reflect.Type) structPlan {
+	plans.RLock()
+	plan, ok := plans.m[t]
+	plans.RUnlock()
+	if ok {
+		return plan
+	}
+	plan = make(structPlan)
+	addFields(plan, t, nil)
+	plans.Lock()
+	plans.m[t] = plan
+	plans.Unlock()
+	return plan
+}
+
+func isStructValue(t reflect.Type) bool {
+	return t == timeType || t == timestampType || t == dateType ||
+		reflect.PtrTo(t).Implements(scannerType) || GetDecoder(t) != nil
+}
+
+func addFields(plan structPlan, t reflect.Type, index []int) {
+	var embedded []reflect.StructField
+	for ii := 0; ii < t.NumField(); ii++ {
+		sf := t.Field(ii)
+		tag := sf.Tag.Get("mysql")
+		if tag == "-" || sf.PkgPath != "" && !sf.Anonymous {
+			// Skipped or unexported field
+			continue
+		}
+		sf.Index = append(append([]int(nil), index...), ii)
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct &&
+			!isStructValue(sf.Type) {
+			// Fields of embedded struct are added after fields of t, so
+			// fields of t take precedence.
+			embedded = append(embedded, sf)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = sf.Name
+		}
+		name = strings.ToLower(name)
+		if _, ok := plan[name]; ok {
+			continue
+		}
+		plan[name] = &fieldPlan{
+			name:  sf.Name,
+			index: sf.Index,
+			set:   makeSetter(sf.Type),
+		}
+	}
+	for _, sf := range embedded {
+		addFields(plan, sf.Type, sf.Index)
+	}
+}
+
+func makeSetter(t reflect.Type) fieldSetter {
+	if dec := GetDecoder(t); dec != nil {
+		return func(v reflect.Value, row Row, nn int) error {
+			return dec(v.Addr().Interface(), row[nn])
+		}
+	}
+	if reflect.PtrTo(t).Implements(scannerType) {
+		return setScanner
+	}
+	switch t {
+	case timeType:
+		return func(v reflect.Value, row Row, nn int) error {
+			tt, err := row.LocaltimeErr(nn)
+			v.Set(reflect.ValueOf(tt))
+			return err
+		}
+	case timestampType:
+		return func(v reflect.Value, row Row, nn int) error {
+			var tt time.Time
+			if ts, ok := row[nn].(Timestamp); ok {
+				tt = ts.Time
+			} else {
+				var err error
+				if tt, err = row.LocaltimeErr(nn); err != nil {
+					return err
+				}
+			}
+			v.Set(reflect.ValueOf(Timestamp{tt}))
+			return nil
+		}
+	case dateType:
+		return func(v reflect.Value, row Row, nn int) error {
+			d, err := row.DateErr(nn)
+			v.Set(reflect.ValueOf(d))
+			return err
+		}
+	case durationType:
+		return func(v reflect.Value, row Row, nn int) error {
+			d, err := row.DurationErr(nn)
+			v.SetInt(int64(d))
+			return err
+		}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		set := makeSetter(t.Elem())
+		if set == nil {
+			return nil
+		}
+		return func(v reflect.Value, row Row, nn int) error {
+			if row[nn] == nil {
+				v.Set(reflect.Zero(v.Type()))
+				return nil
+			}
+			p := reflect.New(v.Type().Elem())
+			if err := set(p.Elem(), row, nn); err != nil {
+				return err
+			}
+			v.Set(p)
+			return nil
+		}
+	case reflect.String:
+		return func(v reflect.Value, row Row, nn int) error {
+			v.SetString(row.Str(nn))
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return func(v reflect.Value, row Row, nn int) error {
+			i, err := row.Int64Err(nn)
+			if err != nil {
+				return err
+			}
+			if v.OverflowInt(i) {
+				return strconv.ErrRange
+			}
+			v.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return func(v reflect.Value, row Row, nn int) error {
+			u, err := row.Uint64Err(nn)
+			if err != nil {
+				return err
+			}
+			if v.OverflowUint(u) {
+				return strconv.ErrRange
+			}
+			v.SetUint(u)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(v reflect.Value, row Row, nn int) error {
+			f, err := row.FloatErr(nn)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(f)
+			return nil
+		}
+	case reflect.Bool:
+		return func(v reflect.Value, row Row, nn int) error {
+			i, err := row.Int64Err(nn)
+			v.SetBool(i != 0)
+			return err
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(v reflect.Value, row Row, nn int) error {
+				if row[nn] == nil {
+					v.SetBytes(nil)
+					return nil
+				}
+				// Copy, so the field doesn't share memory with the row
+				v.SetBytes(append([]byte{}, row.Bin(nn)...))
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// Converts value from row to the value accepted by sql.Scanner.
+func scannerValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case Date:
+		if v.IsZero() {
+			return nil
+		}
+		return v.Localtime()
+	case Timestamp:
+		return v.Time
+	}
+	return val
+}
+
+func setScanner(v reflect.Value, row Row, nn int) error {
+	return v.Addr().Interface().(sql.Scanner).Scan(scannerValue(row[nn]))
+}
+
+// Column to field mapping for a result
+type colPlan []*fieldPlan
+
+func makeColPlan(fields []*Field, typ reflect.Type) colPlan {
+	plan := getPlan(typ)
+	cp := make(colPlan, len(fields))
+	for ii, f := range fields {
+		cp[ii] = plan[strings.ToLower(f.Name)]
+	}
+	return cp
+}
+
+func (cp colPlan) scan(row Row, v reflect.Value) error {
+	if len(row) != len(cp) {
+		return errors.New("mysql: wrong length of row")
+	}
+	for ii, fp := range cp {
+		if fp == nil {
+			continue
+		}
+		if fp.set == nil {
+			return fmt.Errorf("mysql: unsupported type of field %s", fp.name)
+		}
+		if err := fp.set(v.FieldByIndex(fp.index), row, ii); err != nil {
+			return fmt.Errorf("mysql: can't decode column %d into field %s: %s",
+				ii, fp.name, err)
+		}
+	}
+	return nil
+}
+
+func structValue(dst interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return v, errors.New("mysql: destination isn't a pointer to struct")
+	}
+	return v.Elem(), nil
+}
+
+// Decodes row into the struct pointed by dst. Columns are mapped to the
+// exported fields of the struct by `mysql:"name"` tag or by case-insensitive
+// field name. Fields of embedded structs are treated as fields of the outer
+// struct. Fields tagged `mysql:"-"` are skipped. Columns without
+// corresponding field are ignored.
+//
+// Values are converted using the same rules as Row methods (Int64, Str, Time,
+// Date, Duration...). Supported field types are: intX, uintX, floatX, bool,
+// string, []byte, time.Time, time.Duration, Date, Timestamp, pointers to them,
+// any type that implements sql.Scanner (eg. sql.NullInt64) and types with
+// registered decoder (see RegisterDecoder). NULL is decoded as nil pointer, as
+// zero value for other types or is passed to Scan method or decoder.
+//
+// The type of the struct is analyzed once, during first decoding.
+func ScanStruct(r Result, row Row, dst interface{}) error {
+	v, err := structValue(dst)
+	if err != nil {
+		return err
+	}
+	return makeColPlan(r.Fields(), v.Type()).scan(row, v)
+}
+
+// Reads all rows from the result and appends them to the slice pointed by
+// dst. dst must be a pointer to a slice of structs or pointers to structs
+// (see ScanStruct for decoding rules). If decoding fails, remaining rows are
+// discarded.
+func ScanAll(r Result, dst interface{}) (err error) {
+	sv := reflect.ValueOf(dst)
+	if sv.Kind() != reflect.Ptr || sv.Elem().Kind() != reflect.Slice {
+		return errors.New("mysql: destination isn't a pointer to slice")
+	}
+	sv = sv.Elem()
+	et := sv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return errors.New("mysql: destination isn't a slice of structs")
+	}
+	cp := makeColPlan(r.Fields(), et)
+	row := r.MakeRow()
+	for {
+		if err = r.ScanRow(row); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return
+		}
+		ev := reflect.New(et)
+		if err = cp.scan(row, ev.Elem()); err != nil {
+			r.End()
+			return
+		}
+		if !isPtr {
+			ev = ev.Elem()
+		}
+		sv.Set(reflect.Append(sv, ev))
+	}
+}
+
+// Calls Start and next ScanAll
+func QueryInto(c ConnCommon, dst interface{}, sql string, params ...interface{}) (res Result, err error) {
+	res, err = c.Start(sql, params...)
+	if err != nil {
+		return
+	}
+	err = ScanAll(res, dst)
+	return
+}
+
+// Calls Run and next ScanAll
+func ExecInto(s Stmt, dst interface{}, params ...interface{}) (res Result, err error) {
+	res, err = s.Run(params...)
+	if err != nil {
+		return
+	}
+	err = ScanAll(res, dst)
+	return
+}
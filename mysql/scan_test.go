@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type scanBase struct {
+	Id      int
+	Created time.Time
+}
+
+type scanTest struct {
+	scanBase
+	Name    string `mysql:"user_name"`
+	Score   *float64
+	Note    sql.NullString
+	Day     Date
+	Elapsed time.Duration
+	Active  bool
+	Data    []byte
+	Ignored string `mysql:"-"`
+}
+
+func scanFields(names ...string) []*Field {
+	fields := make([]*Field, len(names))
+	for ii, n := range names {
+		fields[ii] = &Field{Name: n}
+	}
+	return fields
+}
+
+func TestScanStruct(t *testing.T) {
+	fields := scanFields("ID", "created", "user_name", "score", "note", "day",
+		"elapsed", "active", "data", "ignored", "unknown")
+	created := time.Date(2012, 9, 26, 10, 20, 30, 0, time.Local)
+	score := 1.5
+
+	// Text protocol row
+	row := Row{[]byte("7"), []byte("2012-09-26 10:20:30"), []byte("Ala"),
+		[]byte("1.5"), nil, []byte("2012-09-26"), []byte("1:02:03"),
+		[]byte("1"), []byte{1, 2}, []byte("x"), []byte("y")}
+	exp := scanTest{
+		scanBase: scanBase{7, created},
+		Name:     "Ala",
+		Score:    &score,
+		Day:      Date{2012, 9, 26},
+		Elapsed:  time.Hour + 2*time.Minute + 3*time.Second,
+		Active:   true,
+		Data:     []byte{1, 2},
+	}
+	var st scanTest
+	cp := makeColPlan(fields, reflect.TypeOf(st))
+	if err := cp.scan(row, reflect.ValueOf(&st).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(st, exp) {
+		t.Fatalf("Text row:\nret=%+v\nexp=%+v", st, exp)
+	}
+
+	// Binary protocol row
+	row = Row{int32(7), created, []byte("Ala"), nil, []byte("note"),
+		Date{2012, 9, 26}, exp.Elapsed, int8(0), nil, nil, nil}
+	exp.Score = nil
+	exp.Note = sql.NullString{String: "note", Valid: true}
+	exp.Active = false
+	exp.Data = nil
+	st = scanTest{}
+	if err := cp.scan(row, reflect.ValueOf(&st).Elem()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(st, exp) {
+		t.Fatalf("Binary row:\nret=%+v\nexp=%+v", st, exp)
+	}
+
+	// Overflow
+	var small struct{ Id int8 }
+	cp = makeColPlan(scanFields("id"), reflect.TypeOf(small))
+	if cp.scan(Row{int32(300)}, reflect.ValueOf(&small).Elem()) == nil {
+		t.Fatal("Expected range error")
+	}
+}
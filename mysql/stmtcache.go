@@ -0,0 +1,9 @@
+package mysql
+
+// Statistics of prepared statement cache (see Conn.PrepareCached)
+type StmtCacheStats struct {
+	Hits      uint64 // Number of statements found in the cache
+	Misses    uint64 // Number of statements prepared by PrepareCached
+	Evictions uint64 // Number of statements deleted from the cache
+	Len       int    // Current number of statements in the cache
+}
@@ -137,7 +137,8 @@ func DurationString(d time.Duration) string {
 	return fmt.Sprintf("%d:%02d:%02d.%09d", hour, min, sec, ns)
 }
 
-// Parse duration from MySQL string format [+-]H+:MM:SS[.UUUUUUUUU].
+// Parse duration from MySQL string format [+-]H+:MM:SS[.U] (fractional part
+// can have 1 to 9 digits).
 // Leading and trailing spaces are ignored. If format is invalid returns nil.
 func ParseDuration(str string) (dur time.Duration, err error) {
 	str = strings.TrimSpace(str)
@@ -162,7 +163,7 @@ func ParseDuration(str string) (dur time.Duration, err error) {
 	} else {
 		goto invalid
 	}
-	if len(str) != 5 && len(str) != 15 || str[2] != ':' {
+	if len(str) != 5 && (len(str) < 7 || len(str) > 15) || str[2] != ':' {
 		goto invalid
 	}
 	if i, err = strconv.ParseInt(str[0:2], 10, 64); err != nil {
@@ -180,12 +181,20 @@ func ParseDuration(str string) (dur time.Duration, err error) {
 	}
 	d += i
 	d *= 1e9
-	if len(str) == 15 {
+	if len(str) > 5 {
+		// Fractional part: 1 to 9 digits
 		if str[5] != '.' {
 			goto invalid
 		}
-		if i, err = strconv.ParseInt(str[6:15], 10, 64); err != nil {
-			return
+		i = 0
+		for nn := 6; nn < 15; nn++ {
+			i *= 10
+			if nn < len(str) {
+				if str[nn] < '0' || str[nn] > '9' {
+					goto invalid
+				}
+				i += int64(str[nn] - '0')
+			}
 		}
 		d += i
 	}
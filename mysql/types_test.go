@@ -67,6 +67,11 @@ var times = []sio{
 	sio{"1:00:60", "invalid MySQL TIME string: 1:00:60"},
 	sio{"1:23:45.000111333", "1:23:45.000111333"},
 	sio{"-1:23:45.000111333", "-1:23:45.000111333"},
+	sio{"12:30:00.123456", "12:30:00.123456000"},
+	sio{"-1:00:01.5", "-1:00:01.500000000"},
+	sio{"1:23:45.", "invalid MySQL TIME string: 1:23:45."},
+	sio{"1:23:45.-1", "invalid MySQL TIME string: 1:23:45.-1"},
+	sio{"1:23:45.0001113330", "invalid MySQL TIME string: 1:23:45.0001113330"},
 }
 
 func TestConvDuration(t *testing.T) {
@@ -14,6 +14,23 @@ func syntaxError(ln int) error {
 	return fmt.Errorf("syntax error at line: %d", ln)
 }
 
+// Calls f for every "Key: value" item in str. Items are separated by two
+// spaces (this format is used by the server for statistics and information
+// about the result of the command).
+func parseItems(str string, f func(key, val string) error) error {
+	for _, item := range strings.Split(str, "  ") {
+		kv := strings.SplitN(item, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		err := f(strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Creates new conneection handler using configuration in cfgFile. Returns
 // connection handler and map contains unknown options.
 //
@@ -28,6 +45,7 @@ func syntaxError(ln int) error {
 //	# optional: DbName	test
 //	# optional: DbEncd	utf8	
 //	# optional: DbLaddr	127.0.0.1
+//	# optional (may be repeated): DbAttr	program_name myapp
 //
 //	# Your options (returned in unk)
 //
@@ -41,6 +59,7 @@ func NewFromCF(cfgFile string) (con Conn, unk map[string]string, err error) {
 	br := bufio.NewReader(cf)
 	um := make(map[string]string)
 	var proto, laddr, raddr, user, pass, name, encd string
+	var attrs [][2]string
 	for i := 1; ; i++ {
 		buf, isPrefix, e := br.ReadLine()
 		if e != nil {
@@ -83,6 +102,15 @@ func NewFromCF(cfgFile string) (con Conn, unk map[string]string, err error) {
 			name = l
 		case "DbEncd":
 			encd = l
+		case "DbAttr":
+			n = strings.IndexFunc(l, unicode.IsSpace)
+			if n == -1 {
+				err = syntaxError(i)
+				return
+			}
+			attrs = append(attrs, [2]string{
+				l[:n], strings.TrimLeftFunc(l[n:], unicode.IsSpace),
+			})
 		default:
 			um[v] = l
 		}
@@ -100,6 +128,9 @@ func NewFromCF(cfgFile string) (con Conn, unk map[string]string, err error) {
 	if encd != "" {
 		con.Register(fmt.Sprintf("SET NAMES %s", encd))
 	}
+	for _, a := range attrs {
+		con.SetConnAttr(a[0], a[1])
+	}
 	return
 }
 
@@ -166,7 +197,7 @@ func ExecLast(s Stmt, params ...interface{}) (row Row, res Result, err error) {
 }
 
 // Calls r.MakeRow and next r.ScanRow. Doesn't return io.EOF error (returns nil
-// row insted).
+// row insted). Returned row doesn't refer to the connection buffer.
 func GetRow(r Result) (Row, error) {
 	row := r.MakeRow()
 	err := r.ScanRow(row)
@@ -176,6 +207,7 @@ func GetRow(r Result) (Row, error) {
 		}
 		return nil, err
 	}
+	row.detach()
 	return row, nil
 }
 
@@ -200,6 +232,7 @@ func GetLastRow(r Result) (Row, error) {
 		err = r.ScanRow(row)
 	}
 	if err == io.EOF {
+		row.detach()
 		return row, nil
 	}
 	return nil, err
@@ -0,0 +1,113 @@
+package native
+
+import (
+	"github.com/ziutek/mymysql/mysql"
+	"log"
+)
+
+// Sends simple command and reads OK or EOF response
+func (my *Conn) simpleCmd(cmd byte, argv ...interface{}) (err error) {
+	defer catchError(&err)
+
+	if my.net_conn == nil {
+		return NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return UNREADED_REPLY_ERROR
+	}
+
+	// Send command
+	my.sendCmd(cmd, argv...)
+	// Get server response
+	my.getResult(nil, nil)
+	return
+}
+
+// Returns a short status of the server: uptime, number of threads, questions
+// and so on.
+func (my *Conn) Statistics() (st mysql.Statistics, err error) {
+	defer catchError(&err)
+
+	if my.net_conn == nil {
+		return st, NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return st, UNREADED_REPLY_ERROR
+	}
+
+	// Send command
+	my.sendCmd(_COM_STATISTICS)
+	// Response is a plain string (not an OK packet) or an error packet
+	pr := my.newPktReader()
+	if readByte(pr) == 255 {
+		my.getErrorPacket(pr)
+	}
+	pr.unreadByte()
+	str := string(pr.readAll())
+	if my.Debug {
+		log.Printf("[%2d ->] Statistics packet: \"%s\"", my.seq-1, str)
+	}
+	return mysql.ParseStatistics(str)
+}
+
+// Returns the list of the server threads (like SHOW PROCESSLIST).
+func (my *Conn) ProcessList() (rows []mysql.Row, res mysql.Result, err error) {
+	defer catchError(&err)
+
+	if my.net_conn == nil {
+		return nil, nil, NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return nil, nil, UNREADED_REPLY_ERROR
+	}
+
+	// Send command
+	my.sendCmd(_COM_PROCESS_INFO)
+	// Get command response
+	res = my.getResponse()
+	rows, err = mysql.GetRows(res)
+	return
+}
+
+// Asks the server to kill the thread specified by thr_id.
+func (my *Conn) Kill(thr_id uint32) error {
+	return my.simpleCmd(_COM_PROCESS_KILL, thr_id)
+}
+
+// Flushes tables or caches or resets replication server information. flags is
+// a combination of mysql.REFRESH_* values.
+func (my *Conn) Refresh(flags byte) error {
+	return my.simpleCmd(_COM_REFRESH, flags)
+}
+
+// Asks the server to shut down. The connected user must have the SHUTDOWN
+// privilege.
+func (my *Conn) Shutdown() error {
+	return my.simpleCmd(_COM_SHUTDOWN, byte(0)) // SHUTDOWN_DEFAULT
+}
+
+// Instructs the server to write debugging information to the error log. The
+// connected user must have the SUPER privilege.
+func (my *Conn) DumpDebugInfo() error {
+	return my.simpleCmd(_COM_DEBUG)
+}
+
+// Enables or disables support for multiple statements separated by ';' in one
+// text query (disabled by default). If the connection isn't established it
+// only sets the option for Connect. Otherwise it changes the option for the
+// current connection using COM_SET_OPTION. In both cases the option is
+// remembered and used by Reconnect.
+//
+// Enable it only when you need it (eg. for migration scripts), because it
+// allows SQL injection bugs to chain additional statements.
+func (my *Conn) SetMultiStatements(on bool) error {
+	my.multi_stmt = on
+	if my.net_conn == nil {
+		return nil
+	}
+	opt := uint16(_MYSQL_OPTION_MULTI_STATEMENTS_OFF)
+	if on {
+		opt = _MYSQL_OPTION_MULTI_STATEMENTS_ON
+	}
+	return my.simpleCmd(_COM_SET_OPTION, opt)
+}
@@ -1,10 +1,16 @@
 package native
 
 import (
+	"bufio"
 	"bytes"
+	"database/sql"
+	"fmt"
 	"github.com/ziutek/mymysql/mysql"
+	"io"
 	"math"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -314,3 +320,257 @@ func TestEscapeQuotes(t *testing.T) {
 		t.Fatalf("escapeString: ret='%s' exp='%s'", out, exp)
 	}
 }
+
+func TestEncodeConnAttrs(t *testing.T) {
+	attrs := map[string]string{"b": "22", "a": "1"}
+	exp := []byte{9, 1, 'a', 1, '1', 1, 'b', 2, '2', '2'}
+	out := encodeConnAttrs(attrs)
+	if !bytes.Equal(out, exp) {
+		t.Fatalf("encodeConnAttrs: ret=%v exp=%v", out, exp)
+	}
+}
+
+func newNamedStmt(names ...string) *Stmt {
+	stmt := &Stmt{
+		params:      make([]*paramValue, len(names)),
+		param_count: len(names),
+		names:       names,
+		name_map:    make(map[string][]int),
+	}
+	for ii, name := range names {
+		stmt.name_map[name] = append(stmt.name_map[name], ii)
+	}
+	return stmt
+}
+
+func TestBindNamed(t *testing.T) {
+	stmt := newNamedStmt("id", "name", "id")
+	err := stmt.BindNamed(map[string]interface{}{"id": 7, "name": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stmt.params[0].typ != _INT_TYPE || stmt.params[2].typ != _INT_TYPE ||
+		stmt.params[1].typ != MYSQL_TYPE_STRING || !stmt.binded {
+		t.Fatalf("Bad binding: %+v", stmt.params)
+	}
+	if stmt.BindNamed(map[string]interface{}{"id": 7}) == nil {
+		t.Fatal("No error for missing parameter")
+	}
+	err = stmt.BindNamed(map[string]interface{}{"id": 7, "name": "x", "a": 1})
+	if err == nil {
+		t.Fatal("No error for unknown parameter")
+	}
+	if stmt.binded {
+		t.Fatal("Statement binded after error")
+	}
+
+	type S struct {
+		Id       int
+		UserName string `mysql:"name"`
+		Skip     bool   `mysql:"-"`
+	}
+	stmt.Bind(&S{1, "a", true})
+	if stmt.params[1].typ != MYSQL_TYPE_STRING || !stmt.binded {
+		t.Fatalf("Bad struct binding: %+v", stmt.params)
+	}
+
+	if newNamedStmt().BindNamed(nil) == nil {
+		t.Fatal("No error for positional statement")
+	}
+}
+
+type testMoney int64 // Cents
+
+func init() {
+	mysql.RegisterEncoder(func(m testMoney) (interface{}, error) {
+		return fmt.Sprintf("%d.%02d", m/100, m%100), nil
+	})
+}
+
+func TestBindConv(t *testing.T) {
+	ns := sql.NullString{String: "a", Valid: true}
+	m := testMoney(1234)
+	var np *sql.NullInt64
+	params := []*paramValue{
+		bindValue(reflect.ValueOf(&ns).Elem()),
+		bindValue(reflect.ValueOf(&m).Elem()),
+		bindValue(reflect.ValueOf(&np).Elem()),
+	}
+	exp := []uint16{MYSQL_TYPE_STRING, MYSQL_TYPE_STRING, MYSQL_TYPE_NULL}
+	for ii, p := range params {
+		if p.conv == nil {
+			t.Fatalf("%d: no converter", ii)
+		}
+		if !convValue(p) || p.typ != exp[ii] {
+			t.Fatalf("%d: typ=%d exp=%d", ii, p.typ, exp[ii])
+		}
+	}
+	if params[1].Len() != 6 {
+		t.Fatalf("Bad length of registered type: %d", params[1].Len())
+	}
+	// Values are obtained during every execution
+	ns.Valid = false
+	np = &sql.NullInt64{Int64: 1, Valid: true}
+	if !convValue(params[0]) || params[0].typ != MYSQL_TYPE_NULL {
+		t.Fatal("NullString: type not changed")
+	}
+	if !convValue(params[2]) || params[2].typ != MYSQL_TYPE_LONGLONG {
+		t.Fatal("*NullInt64: type not changed")
+	}
+	if convValue(params[2]) {
+		t.Fatal("*NullInt64: unexpected type change")
+	}
+}
+
+// Returns COM_STMT_SEND_LONG_DATA packets of statement 5 with data sent for
+// parameter pnum in chunks of chunk bytes.
+func longDataPkts(pnum byte, data string, chunk int) []byte {
+	var pkts []byte
+	for first := true; first || len(data) != 0; first = false {
+		n := chunk
+		if n > len(data) {
+			n = len(data)
+		}
+		l := 7 + n
+		pkts = append(pkts, byte(l), byte(l>>8), byte(l>>16), 0,
+			_COM_STMT_SEND_LONG_DATA, 5, 0, 0, 0, pnum, 0)
+		pkts = append(pkts, data[:n]...)
+		data = data[n:]
+	}
+	return pkts
+}
+
+func TestSendReaders(t *testing.T) {
+	var out bytes.Buffer
+	my := &Conn{wr: bufio.NewWriter(&out), max_allowed: 1 << 20}
+	stmt := &Stmt{my: my, id: 5, param_count: 4}
+	long := strings.Repeat("0123456789abcdef", longDataChunk/16)
+	var nilReader *strings.Reader
+	stmt.params = []*paramValue{
+		bindValue(addrValue(strings.NewReader(long + "x"))),
+		bindValue(addrValue(&nilReader)),
+		bindValue(addrValue(strings.NewReader(""))),
+		bindValue(addrValue(strings.NewReader(long))),
+	}
+	for ii, p := range stmt.params {
+		if p.reader == nil || p.typ != MYSQL_TYPE_BLOB || p.Len() != 0 {
+			t.Fatalf("%d: bad reader binding: %+v", ii, p)
+		}
+	}
+	if err := stmt.sendReaders(); err != nil {
+		t.Fatal(err)
+	}
+	// Chunks of longDataChunk bytes. Empty reader sends empty chunk.
+	var exp []byte
+	exp = append(exp, longDataPkts(0, long+"x", longDataChunk)...)
+	exp = append(exp, longDataPkts(2, "", longDataChunk)...)
+	exp = append(exp, longDataPkts(3, long, longDataChunk)...)
+	if !bytes.Equal(out.Bytes(), exp) {
+		t.Fatalf("Bad packets (%d bytes, expected %d)", out.Len(), len(exp))
+	}
+	// Buffer is reused
+	buf := my.long_data
+	if len(buf) != longDataChunk {
+		t.Fatal("Bad buffer length:", len(buf))
+	}
+	out.Reset()
+	stmt.params = stmt.params[:1]
+	stmt.params[0] = bindValue(addrValue(strings.NewReader("abcdefg")))
+	if err := stmt.sendReaders(); err != nil {
+		t.Fatal(err)
+	}
+	if &my.long_data[0] != &buf[0] {
+		t.Fatal("Buffer not reused")
+	}
+
+	// Chunks limited by max_allowed_packet
+	out.Reset()
+	my.max_allowed = 10
+	stmt.params[0] = bindValue(addrValue(strings.NewReader("abcdefg")))
+	if err := stmt.sendReaders(); err != nil {
+		t.Fatal(err)
+	}
+	if exp = longDataPkts(0, "abcdefg", 3); !bytes.Equal(out.Bytes(), exp) {
+		t.Fatalf("ret=%v\nexp=%v", out.Bytes(), exp)
+	}
+	my.max_allowed = 7
+	if err := stmt.sendReaders(); err != SMALL_PKT_SIZE_ERROR {
+		t.Fatal("Expected SMALL_PKT_SIZE_ERROR, got:", err)
+	}
+}
+
+func TestRunReaderReprepare(t *testing.T) {
+	nc, peer := net.Pipe()
+	defer nc.Close()
+	defer peer.Close()
+
+	need_reprepare := []byte("\xff\x4f\x06#HY000Prepared statement needs to be re-prepared")
+	prepare_ok := []byte{0, 4, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+	for _, seeker := range []bool{true, false} {
+		var in []byte
+		in = append(in, response(need_reprepare)...)
+		in = append(in, response(
+			prepare_ok, fieldPkt("?", MYSQL_TYPE_VAR_STRING), eofPkt,
+		)...)
+		if seeker {
+			in = append(in, response(okPkt(1))...)
+		}
+		var out bytes.Buffer
+		my := &Conn{
+			net_conn:    nc,
+			rd:          bufio.NewReader(bytes.NewReader(in)),
+			wr:          bufio.NewWriter(&out),
+			stmt_map:    make(map[uint32]*Stmt),
+			max_allowed: 1 << 20,
+		}
+		stmt := &Stmt{
+			my: my, id: 3, sql: "insert T values (?)",
+			param_count: 1, params: make([]*paramValue, 1),
+		}
+		my.stmt_map[stmt.id] = stmt
+		var rd io.Reader = strings.NewReader("abc")
+		if !seeker {
+			rd = struct{ io.Reader }{rd}
+		}
+		_, err := stmt.Run(rd)
+
+		// Commands and data of COM_STMT_SEND_LONG_DATA
+		var cmds []byte
+		var data []string
+		for buf := out.Bytes(); len(buf) != 0; {
+			n := int(buf[0]) | int(buf[1])<<8 | int(buf[2])<<16
+			pkt := buf[4 : 4+n]
+			cmds = append(cmds, pkt[0])
+			if pkt[0] == _COM_STMT_SEND_LONG_DATA {
+				data = append(data, string(pkt[7:]))
+			}
+			buf = buf[4+n:]
+		}
+		exp_cmds := []byte{
+			_COM_STMT_SEND_LONG_DATA, _COM_STMT_EXECUTE,
+			_COM_STMT_PREPARE, _COM_STMT_CLOSE,
+		}
+		exp_data := []string{"abc"}
+		if seeker {
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Data are sent again to the reprepared statement
+			exp_cmds = append(exp_cmds,
+				_COM_STMT_SEND_LONG_DATA, _COM_STMT_EXECUTE,
+			)
+			exp_data = append(exp_data, "abc")
+		} else {
+			e, ok := err.(*mysql.Error)
+			if !ok || e.Code != mysql.ER_NEED_REPREPARE {
+				t.Fatal("Expected ER_NEED_REPREPARE, got:", err)
+			}
+		}
+		if !bytes.Equal(cmds, exp_cmds) || !reflect.DeepEqual(data, exp_data) {
+			t.Fatalf("seeker=%t: bad commands %v, data %q", seeker, cmds, data)
+		}
+		if stmt.id != 4 || my.stmt_map[4] != stmt {
+			t.Fatal("Statement not reprepared")
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package native
 
 import (
+	"database/sql/driver"
 	"github.com/ziutek/mymysql/mysql"
+	"io"
 	"reflect"
 	"time"
 )
@@ -13,13 +15,120 @@ var (
 	durationType  = reflect.TypeOf(time.Duration(0))
 	blobType      = reflect.TypeOf(mysql.Blob{})
 	rawType       = reflect.TypeOf(mysql.Raw{})
+	valuerType    = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	readerType    = reflect.TypeOf((*io.Reader)(nil)).Elem()
 )
 
+// Returns function that converts addressable value of type typ using
+// registered encoder or driver.Valuer interface. Returns nil if typ isn't
+// registered and doesn't implement driver.Valuer.
+func converter(typ reflect.Type) func(reflect.Value) (interface{}, error) {
+	if enc := mysql.GetEncoder(typ); enc != nil {
+		return func(v reflect.Value) (interface{}, error) {
+			return enc(v.Interface())
+		}
+	}
+	if reflect.PtrTo(typ).Implements(valuerType) {
+		return func(v reflect.Value) (interface{}, error) {
+			return v.Addr().Interface().(driver.Valuer).Value()
+		}
+	}
+	return nil
+}
+
+// Returns true if typ should be binded as one value, not as a struct of
+// parameters.
+func isValueType(typ reflect.Type) bool {
+	return typ == timeType || typ == dateType || typ == timestampType ||
+		typ == rawType || converter(typ) != nil ||
+		reflect.PtrTo(typ).Implements(readerType)
+}
+
+// Returns function that returns io.Reader binded as val (addressable value,
+// pointer or interface) or nil if val isn't io.Reader.
+func readerParam(val reflect.Value) func() io.Reader {
+	typ := val.Type()
+	if typ.Kind() == reflect.Interface {
+		if !typ.Implements(readerType) {
+			return nil
+		}
+		return func() io.Reader {
+			rd, _ := val.Interface().(io.Reader)
+			return rd
+		}
+	}
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	if !reflect.PtrTo(typ).Implements(readerType) {
+		return nil
+	}
+	return func() io.Reader {
+		v := val
+		if isPtr {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		return v.Addr().Interface().(io.Reader)
+	}
+}
+
+// Returns function that obtains a value to send from val (addressable value
+// or pointer) or nil if val doesn't need conversion.
+func convParam(val reflect.Value) func() (interface{}, error) {
+	typ := val.Type()
+	isPtr := typ.Kind() == reflect.Ptr
+	if isPtr {
+		typ = typ.Elem()
+	}
+	conv := converter(typ)
+	if conv == nil {
+		return nil
+	}
+	return func() (interface{}, error) {
+		v := val
+		if isPtr {
+			if v.IsNil() {
+				return nil, nil
+			}
+			v = v.Elem()
+		}
+		return conv(v)
+	}
+}
+
+// Converts param using param.conv. Returns true if the type of param was
+// changed.
+func convValue(param *paramValue) bool {
+	v, err := param.conv()
+	if err != nil {
+		panic(err)
+	}
+	pv := bindValue(addrValue(v))
+	if pv.conv != nil {
+		panic(BIND_UNK_TYPE)
+	}
+	changed := pv.typ != param.typ
+	param.typ, param.addr, param.raw, param.length = pv.typ, pv.addr, pv.raw,
+		pv.length
+	return changed
+}
+
 // val should be an addressable value
 func bindValue(val reflect.Value) (out *paramValue) {
 	if !val.IsValid() {
 		return &paramValue{typ: MYSQL_TYPE_NULL}
 	}
+	if conv := convParam(val); conv != nil {
+		return &paramValue{conv: conv}
+	}
+	if rd := readerParam(val); rd != nil {
+		// Value isn't sent in execute packet so it is NULL there
+		return &paramValue{typ: MYSQL_TYPE_BLOB, reader: rd}
+	}
 	typ := val.Type()
 	out = new(paramValue)
 	if typ.Kind() == reflect.Ptr {
@@ -100,6 +100,45 @@ func readNullLCB(rd io.Reader) (lcb uint64, null bool) {
 	return
 }
 
+// Decodes length coded binary that starts at buf[0]. Returns its value and
+// size.
+func decodeNullLCB(buf []byte) (lcb uint64, null bool, n int) {
+	if len(buf) == 0 {
+		panic(PKT_ERROR)
+	}
+	switch buf[0] {
+	case 251:
+		return 0, true, 1
+	case 252:
+		n = 3
+	case 253:
+		n = 4
+	case 254:
+		n = 9
+	default:
+		return uint64(buf[0]), false, 1
+	}
+	if len(buf) < n {
+		panic(PKT_ERROR)
+	}
+	return DecodeU64(buf[1:n]), false, n
+}
+
+// Decodes length coded binary string that starts at buf[0]. Returns the
+// string (slice of buf) and the number of bytes it takes in buf.
+func decodeNullBin(buf []byte) (bin []byte, null bool, n int) {
+	var l uint64
+	l, null, n = decodeNullLCB(buf)
+	if null {
+		return
+	}
+	if l > uint64(len(buf)-n) {
+		panic(PKT_ERROR)
+	}
+	end := n + int(l)
+	return buf[n:end:end], false, end
+}
+
 func readLCB(rd io.Reader) uint64 {
 	lcb, null := readNullLCB(rd)
 	if null {
@@ -253,24 +292,27 @@ func writeNT(wr io.Writer, v interface{}) {
 
 // Date and time
 
-func readDuration(rd io.Reader) time.Duration {
-	dlen := readByte(rd)
+// Checks the first byte of binary time value and returns length of the rest
+func durationLen(dlen byte) int {
 	switch dlen {
 	case 251:
 		// Null
 		panic(UNEXP_NULL_TIME_ERROR)
-	case 0:
+	case 0, 5, 8, 12:
+		// 00:00:00 or properly time length
+		return int(dlen)
+	}
+	panic(WRONG_DATE_LEN_ERROR)
+}
+
+// Decodes binary time value (without its length byte)
+func decodeDuration(buf []byte) time.Duration {
+	if len(buf) == 0 {
 		// 00:00:00
 		return 0
-	case 5, 8, 12:
-		// Properly time length
-	default:
-		panic(WRONG_DATE_LEN_ERROR)
 	}
-	buf := make([]byte, dlen)
-	readFull(rd, buf)
 	tt := int64(0)
-	switch dlen {
+	switch len(buf) {
 	case 12:
 		// Nanosecond part
 		tt += int64(DecodeU32(buf[8:]))
@@ -282,7 +324,6 @@ func readDuration(rd io.Reader) time.Duration {
 	case 5:
 		// Day part
 		tt += int64(DecodeU32(buf[1:5])) * (24 * 3600 * 1e9)
-		fallthrough
 	}
 	if buf[0] != 0 {
 		tt = -tt
@@ -290,6 +331,10 @@ func readDuration(rd io.Reader) time.Duration {
 	return time.Duration(tt)
 }
 
+func readDuration(rd io.Reader) time.Duration {
+	return decodeDuration(read(rd, durationLen(readByte(rd))))
+}
+
 func EncodeDuration(d time.Duration) []byte {
 	buf := make([]byte, 13)
 	if d < 0 {
@@ -335,25 +380,28 @@ func lenDuration(d time.Duration) int {
 	return 6
 }
 
-func readTime(rd io.Reader) time.Time {
-	dlen := readByte(rd)
+// Checks the first byte of binary datetime value and returns length of the
+// rest
+func timeLen(dlen byte) int {
 	switch dlen {
 	case 251:
 		// Null
 		panic(UNEXP_NULL_DATE_ERROR)
-	case 0:
+	case 0, 4, 7, 11:
+		// 0000-00-00 or properly datetime length
+		return int(dlen)
+	}
+	panic(WRONG_DATE_LEN_ERROR)
+}
+
+// Decodes binary datetime value (without its length byte)
+func decodeTime(buf []byte) time.Time {
+	if len(buf) == 0 {
 		// return 0000-00-00 converted to time.Time zero
 		return time.Time{}
-	case 4, 7, 11:
-		// Properly datetime length
-	default:
-		panic(WRONG_DATE_LEN_ERROR)
 	}
-
-	buf := make([]byte, dlen)
-	readFull(rd, buf)
 	var y, mon, d, h, m, s, n int
-	switch dlen {
+	switch len(buf) {
 	case 11:
 		// 2006-01-02 15:04:05.001004005
 		n = int(DecodeU32(buf[7:]))
@@ -373,6 +421,10 @@ func readTime(rd io.Reader) time.Time {
 	return time.Date(y, time.Month(mon), d, h, m, s, n, time.Local)
 }
 
+func readTime(rd io.Reader) time.Time {
+	return decodeTime(read(rd, timeLen(readByte(rd))))
+}
+
 func encodeNonzeroTime(y int16, mon, d, h, m, s byte, n uint32) []byte {
 	buf := make([]byte, 12)
 	switch {
@@ -423,11 +475,15 @@ func lenTime(t time.Time) int {
 	return 5
 }
 
-func readDate(rd io.Reader) mysql.Date {
-	y, m, d := readTime(rd).Date()
+func dateOf(t time.Time) mysql.Date {
+	y, m, d := t.Date()
 	return mysql.Date{int16(y), byte(m), byte(d)}
 }
 
+func readDate(rd io.Reader) mysql.Date {
+	return dateOf(readTime(rd))
+}
+
 func EncodeDate(d mysql.Date) []byte {
 	if d.IsZero() {
 		return []byte{0} // MySQL zero
@@ -20,7 +20,8 @@ func (my *Conn) sendCmd(cmd byte, argv ...interface{}) {
 		writeU16(pw, argv[1].(uint16)) // Parameter number
 		writeBS(pw, argv[2])           // payload
 
-	case _COM_QUIT, _COM_STATISTICS, _COM_PROCESS_INFO, _COM_DEBUG, _COM_PING:
+	case _COM_QUIT, _COM_STATISTICS, _COM_PROCESS_INFO, _COM_DEBUG, _COM_PING,
+		_COM_RESET_CONNECTION:
 		pw := my.newPktWriter(1)
 		writeByte(pw, cmd)
 
@@ -65,14 +66,21 @@ func (my *Conn) sendCmd(cmd byte, argv ...interface{}) {
 		writeU16(pw, argv[0].(uint16))
 
 	case _COM_CHANGE_USER:
-		pw := my.newPktWriter(
-			1 + lenBS(argv[0]) + 1 + lenLC(argv[1]) + lenBS(argv[2]) + 1,
-		)
+		pay_len := 1 + lenBS(argv[0]) + 1 + lenLC(argv[1]) + lenBS(argv[2]) + 1
+		pay_len += 2
+		if len(argv) > 4 {
+			pay_len += lenBS(argv[4])
+		}
+
+		pw := my.newPktWriter(pay_len)
 		writeByte(pw, cmd)
-		writeNT(pw, argv[0]) // User name
-		writeLC(pw, argv[1]) // Scrambled password
-		writeNT(pw, argv[2]) // Database name
-		//writeU16(pw, argv[3]) // Character set number (since 5.1.23?)
+		writeNT(pw, argv[0])           // User name
+		writeLC(pw, argv[1])           // Scrambled password
+		writeNT(pw, argv[2])           // Database name
+		writeU16(pw, argv[3].(uint16)) // Character set number
+		if len(argv) > 4 {
+			writeBS(pw, argv[4]) // Connection attributes
+		}
 
 	case _COM_BINLOG_DUMP:
 		pay_len := 1 + 4 + 2 + 4
@@ -22,6 +22,9 @@ const (
 	_CLIENT_SECURE_CONN                  // New 4.1 authentication
 	_CLIENT_MULTI_STATEMENTS             // Enable/disable multi-stmt support
 	_CLIENT_MULTI_RESULTS                // Enable/disable multi-results
+	_CLIENT_PS_MULTI_RESULTS             // Multi-results in PS-protocol
+	_CLIENT_PLUGIN_AUTH                  // Client supports plugin authentication
+	_CLIENT_CONNECT_ATTRS                // Client supports connection attributes
 )
 
 // Commands - borrowed from GoMySQL
@@ -54,6 +57,13 @@ const (
 	_COM_STMT_RESET          = 0x1a
 	_COM_SET_OPTION          = 0x1b
 	_COM_STMT_FETCH          = 0x1c
+	_COM_RESET_CONNECTION    = 0x1f
+)
+
+// Options for COM_SET_OPTION
+const (
+	_MYSQL_OPTION_MULTI_STATEMENTS_ON  = 0
+	_MYSQL_OPTION_MULTI_STATEMENTS_OFF = 1
 )
 
 // Server status
@@ -70,6 +80,9 @@ const (
 
 	_SERVER_STATUS_DB_DROPPED           = 0x100
 	_SERVER_STATUS_NO_BACKSLASH_ESCAPES = 0x200
+	// Result set contains OUT parameters of a procedure called by prepared
+	// statement
+	_SERVER_PS_OUT_PARAMS = 0x1000
 )
 
 // MySQL protocol types.
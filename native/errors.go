@@ -2,32 +2,56 @@ package native
 
 import (
 	"errors"
+	"github.com/ziutek/mymysql/mysql"
 )
 
 var (
-	SEQ_ERROR             = errors.New("packet sequence error")
-	PKT_ERROR             = errors.New("malformed packet")
-	PKT_LONG_ERROR        = errors.New("packet too long")
-	UNEXP_NULL_LCS_ERROR  = errors.New("unexpected NULL LCS")
-	UNEXP_NULL_LCB_ERROR  = errors.New("unexpected NULL LCB")
-	UNEXP_NULL_DATE_ERROR = errors.New("unexpected NULL DATETIME")
-	UNEXP_NULL_TIME_ERROR = errors.New("unexpected NULL TIME")
-	UNK_RESULT_PKT_ERROR  = errors.New("unexpected or unknown result packet")
-	NOT_CONN_ERROR        = errors.New("not connected")
-	ALREDY_CONN_ERROR     = errors.New("not connected")
-	BAD_RESULT_ERROR      = errors.New("unexpected result")
-	UNREADED_REPLY_ERROR  = errors.New("reply is not completely read")
-	BIND_COUNT_ERROR      = errors.New("wrong number of values for bind")
-	BIND_UNK_TYPE         = errors.New("unknown value type for bind")
-	ROW_LENGTH_ERROR      = errors.New("wrong length of row slice")
-	BAD_COMMAND_ERROR     = errors.New("comand isn't text SQL nor *Stmt")
-	WRONG_DATE_LEN_ERROR  = errors.New("wrong datetime/timestamp length")
-	WRONG_TIME_LEN_ERROR  = errors.New("wrong time length")
-	UNK_MYSQL_TYPE_ERROR  = errors.New("unknown MySQL type")
-	WRONG_PARAM_NUM_ERROR = errors.New("wrong parameter number")
-	UNK_DATA_TYPE_ERROR   = errors.New("unknown data source type")
-	SMALL_PKT_SIZE_ERROR  = errors.New("specified packet size is to small")
-	READ_AFTER_EOR_ERROR  = errors.New("previous GetRow call returned nil row")
-	OLD_PROTOCOL_ERROR    = errors.New("server does not support 4.1 protocol")
-	AUTHENTICATION_ERROR  = errors.New("authentication error")
+	SEQ_ERROR               = errors.New("packet sequence error")
+	PKT_ERROR               = errors.New("malformed packet")
+	PKT_LONG_ERROR          = errors.New("packet too long")
+	UNEXP_NULL_LCS_ERROR    = errors.New("unexpected NULL LCS")
+	UNEXP_NULL_LCB_ERROR    = errors.New("unexpected NULL LCB")
+	UNEXP_NULL_DATE_ERROR   = errors.New("unexpected NULL DATETIME")
+	UNEXP_NULL_TIME_ERROR   = errors.New("unexpected NULL TIME")
+	UNK_RESULT_PKT_ERROR    = errors.New("unexpected or unknown result packet")
+	NOT_CONN_ERROR          = errors.New("not connected")
+	ALREDY_CONN_ERROR       = errors.New("not connected")
+	BAD_RESULT_ERROR        = errors.New("unexpected result")
+	UNREADED_REPLY_ERROR    = errors.New("reply is not completely read")
+	BIND_COUNT_ERROR        = errors.New("wrong number of values for bind")
+	BIND_UNK_TYPE           = errors.New("unknown value type for bind")
+	ROW_LENGTH_ERROR        = errors.New("wrong length of row slice")
+	BAD_COMMAND_ERROR       = errors.New("comand isn't text SQL nor *Stmt")
+	WRONG_DATE_LEN_ERROR    = errors.New("wrong datetime/timestamp length")
+	WRONG_TIME_LEN_ERROR    = errors.New("wrong time length")
+	UNK_MYSQL_TYPE_ERROR    = errors.New("unknown MySQL type")
+	WRONG_PARAM_NUM_ERROR   = errors.New("wrong parameter number")
+	UNK_DATA_TYPE_ERROR     = errors.New("unknown data source type")
+	SMALL_PKT_SIZE_ERROR    = errors.New("specified packet size is to small")
+	READ_AFTER_EOR_ERROR    = mysql.READ_AFTER_EOR_ERROR
+	OLD_PROTOCOL_ERROR      = errors.New("server does not support 4.1 protocol")
+	AUTHENTICATION_ERROR    = errors.New("authentication error")
+	NO_NAMED_PARAMS_ERROR   = errors.New("statement has no named parameters")
+	STREAM_NOT_CLOSED_ERROR = errors.New("previous row stream is not closed")
+	STREAM_CLOSED_ERROR     = errors.New("row stream is closed")
+	STREAM_TYPE_ERROR       = errors.New("column value can't be streamed")
+	STREAM_READER_ERROR     = errors.New("column reader isn't valid after next column was requested")
+	PIPELINE_STMT_ERROR     = errors.New("statement doesn't belong to the pipeline connection")
+	STMT_EVICTED_ERROR      = errors.New("statement was evicted from the statement cache")
 )
+
+// Returned by ScanInto if a column value can't be stored in its destination.
+// The row was read (or nothing was read), so scanning can be continued.
+type ScanIntoError struct {
+	Column int    // Number of the column
+	Name   string // Name of the column
+	Err    error
+}
+
+func (e *ScanIntoError) Error() string {
+	return "can't scan column " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *ScanIntoError) Unwrap() error {
+	return e.Err
+}
@@ -1,9 +1,45 @@
 package native
 
 import (
+	"bytes"
 	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
 )
 
+// Version of mymysql reported to the server in _client_version attribute
+const clientVersion = "1.0"
+
+func defaultConnAttrs() map[string]string {
+	return map[string]string{
+		"_client_name":    "mymysql",
+		"_client_version": clientVersion,
+		"_os":             runtime.GOOS,
+		"_pid":            strconv.Itoa(os.Getpid()),
+	}
+}
+
+// Returns connection attributes encoded as length coded binary contains
+// length coded key/value pairs. Keys are sorted, so the result is
+// deterministic.
+func encodeConnAttrs(attrs map[string]string) []byte {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var kv bytes.Buffer
+	for _, k := range keys {
+		writeStr(&kv, k)
+		writeStr(&kv, attrs[k])
+	}
+	var buf bytes.Buffer
+	writeBin(&buf, kv.Bytes())
+	return buf.Bytes()
+}
+
 func (my *Conn) init() {
 	my.seq = 0 // Reset sequence number, mainly for reconnect
 	if my.Debug {
@@ -17,10 +53,12 @@ func (my *Conn) init() {
 	my.info.thr_id = readU32(pr)
 	readFull(pr, my.info.scramble[0:8])
 	read(pr, 1)
-	my.info.caps = readU16(pr)
+	my.info.caps = uint32(readU16(pr))
 	my.info.lang = readByte(pr)
 	my.status = readU16(pr)
-	read(pr, 13)
+	// Upper part of capabilities (zero for servers older than 5.5)
+	my.info.caps |= uint32(readU16(pr)) << 16
+	read(pr, 11)
 	if my.info.caps&_CLIENT_PROTOCOL_41 != 0 {
 		readFull(pr, my.info.scramble[8:])
 	}
@@ -45,16 +83,26 @@ func (my *Conn) auth() {
 			_CLIENT_LONG_FLAG |
 			_CLIENT_TRANSACTIONS |
 			_CLIENT_SECURE_CONN |
-			_CLIENT_MULTI_STATEMENTS |
-			_CLIENT_MULTI_RESULTS)
+			_CLIENT_MULTI_RESULTS |
+			_CLIENT_PS_MULTI_RESULTS)
+	if my.multi_stmt {
+		flags |= _CLIENT_MULTI_STATEMENTS
+	}
 	// Reset flags not supported by server
-	flags &= uint32(my.info.caps) | 0xffff0000
+	flags &= my.info.caps | 0xffff0000
 	scrPasswd := encryptedPasswd(my.passwd, my.info.scramble)
 	pay_len := 4 + 4 + 1 + 23 + len(my.user) + 1 + 1 + len(scrPasswd)
 	if len(my.dbname) > 0 {
 		pay_len += len(my.dbname) + 1
 		flags |= _CLIENT_CONNECT_WITH_DB
 	}
+	var attrs []byte
+	if my.info.caps&_CLIENT_CONNECT_ATTRS != 0 {
+		attrs = encodeConnAttrs(my.conn_attrs)
+		pay_len += len(attrs)
+		flags |= _CLIENT_CONNECT_ATTRS
+	}
+	my.flags = flags
 	pw := my.newPktWriter(pay_len)
 	writeU32(pw, flags)
 	writeU32(pw, uint32(my.max_pkt_size))
@@ -65,18 +113,17 @@ func (my *Conn) auth() {
 	if len(my.dbname) > 0 {
 		writeNTS(pw, my.dbname)
 	}
-	if len(my.dbname) > 0 {
-		pay_len += len(my.dbname) + 1
-		flags |= _CLIENT_CONNECT_WITH_DB
+	if attrs != nil {
+		write(pw, attrs) // Connection attributes
 	}
 	return
 }
 
-func (my *Conn) oldPasswd() {
+func (my *Conn) oldPasswd(passwd string) {
 	if my.Debug {
 		log.Printf("[%2d <-] Password packet", my.seq)
 	}
-	scrPasswd := encryptedOldPassword(my.passwd, my.info.scramble)
+	scrPasswd := encryptedOldPassword(passwd, my.info.scramble)
 	pw := my.newPktWriter(len(scrPasswd) + 1)
 	write(pw, scrPasswd)
 	writeByte(pw, 0)
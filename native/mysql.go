@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"github.com/ziutek/mymysql/mysql"
 	"io"
+	"iter"
 	"net"
 	"reflect"
+	"strings"
 )
 
 type serverInfo struct {
@@ -15,7 +17,7 @@ type serverInfo struct {
 	serv_ver string
 	thr_id   uint32
 	scramble []byte
-	caps     uint16
+	caps     uint32
 	lang     byte
 }
 
@@ -33,21 +35,55 @@ type Conn struct {
 	rd       *bufio.Reader
 	wr       *bufio.Writer
 
-	info serverInfo // MySQL server information
-	seq  byte       // MySQL sequence number
+	info  serverInfo // MySQL server information
+	flags uint32     // Client capabilities sent to the server during auth
+	seq   byte       // MySQL sequence number
 
 	unreaded_reply bool
+	last_res       *Result // Last result returned by getResponse
 
-	init_cmds []string         // MySQL commands/queries executed after connect
-	stmt_map  map[uint32]*Stmt // For reprepare during reconnect
+	// Written packets aren't flushed (pipeline is being sent)
+	no_flush bool
+
+	// Packet reader and buffer reused for every packet/row read
+	pr      pktReader
+	row_buf []byte // Payload of the last row packet
+
+	// Server doesn't support COM_RESET_CONNECTION
+	no_reset_conn bool
+
+	init_cmds  []string          // MySQL commands/queries executed after connect
+	stmt_map   map[uint32]*Stmt  // For reprepare during reconnect
+	conn_attrs map[string]string // Connection attributes sent during auth
+
+	// Statements prepared by PrepareCached
+	stmt_cache stmtCache
 
 	// Current status of MySQL server connection
 	status uint16
 
+	// Multi-statement support enabled (disabled by default)
+	multi_stmt bool
+
+	// Automatic warnings retrieval and warning codes treated as errors
+	auto_warn   bool
+	strict_warn map[uint16]bool
+
+	// Text row values converted to the types used by binary protocol
+	typed_text bool
+
+	// []byte values of rows refer to row_buf (see SetSharedRowBuffer)
+	shared_rows bool
+
 	// Maximum packet size that client can accept from server.
 	// Default 16*1024*1024-1. You may change it before connect.
 	max_pkt_size int
 
+	// Server max_allowed_packet (0 if unknown) and buffer for reading
+	// io.Reader parameters
+	max_allowed int
+	long_data   []byte
+
 	// Debug logging. You may change it at any time.
 	Debug bool
 }
@@ -63,8 +99,10 @@ func New(proto, laddr, raddr, user, passwd string, db ...string) mysql.Conn {
 		user:         user,
 		passwd:       passwd,
 		stmt_map:     make(map[uint32]*Stmt),
+		conn_attrs:   defaultConnAttrs(),
 		max_pkt_size: 16*1024*1024 - 1,
 	}
+	my.stmt_cache.size = DefaultStmtCacheSize
 	if len(db) == 1 {
 		my.dbname = db[0]
 	} else if len(db) > 1 {
@@ -83,6 +121,16 @@ func (my *Conn) Clone() mysql.Conn {
 		c = New(my.proto, my.laddr, my.raddr, my.user, my.passwd, my.dbname).(*Conn)
 	}
 	c.max_pkt_size = my.max_pkt_size
+	c.stmt_cache.size = my.stmt_cache.size
+	c.multi_stmt = my.multi_stmt
+	c.auto_warn = my.auto_warn
+	c.typed_text = my.typed_text
+	c.shared_rows = my.shared_rows
+	c.strict_warn = my.strict_warn
+	c.conn_attrs = make(map[string]string, len(my.conn_attrs))
+	for k, v := range my.conn_attrs {
+		c.conn_attrs[k] = v
+	}
 	c.Debug = my.Debug
 	return c
 }
@@ -96,6 +144,41 @@ func (my *Conn) SetMaxPktSize(new_size int) int {
 	return old_size
 }
 
+// Enables or disables typed text rows. If enabled, values of text protocol
+// results (Start, Query) are converted using field types to the same Go
+// types as values of prepared statement results: intX/uintX (according to
+// UNSIGNED flag), floatX, float64 for DECIMAL, Date, time.Time and
+// time.Duration. Values of other types (strings, blobs, BIT...) stay []byte.
+// Applies to rows read after the call.
+func (my *Conn) SetTypedText(on bool) {
+	my.typed_text = on
+}
+
+// Enables or disables sharing of the row buffer. By default every row read
+// by ScanRow gets its own copy of []byte values. If sharing is enabled,
+// []byte values refer to the connection buffer that is reused for every
+// read row, so they are valid only until the next ScanRow (or any other read
+// from the connection) and rows that should be retained must be copied
+// using Row.Clone. It avoids allocation of row data when many rows are
+// scanned into the same Row. GetRow, GetRows and GetLastRow always return
+// rows that can be retained. Applies to rows read after the call.
+func (my *Conn) SetSharedRowBuffer(on bool) {
+	my.shared_rows = on
+}
+
+// Sets connection attribute that will be sent to the server during next
+// connect (if the server supports them, see
+// performance_schema.session_connect_attrs). Empty value removes the
+// attribute, so you can use it to suppress one of the default attributes:
+// _client_name, _client_version, _os, _pid.
+func (my *Conn) SetConnAttr(name, value string) {
+	if value == "" {
+		delete(my.conn_attrs, name)
+	} else {
+		my.conn_attrs[name] = value
+	}
+}
+
 func (my *Conn) connect() (err error) {
 	defer catchError(&err)
 
@@ -141,6 +224,10 @@ func (my *Conn) connect() (err error) {
 
 	my.rd = bufio.NewReader(my.net_conn)
 	my.wr = bufio.NewWriter(my.net_conn)
+	my.unreaded_reply = false
+	my.last_res = nil
+	my.no_reset_conn = false
+	my.max_allowed = 0
 
 	// Initialisation
 	my.init()
@@ -148,7 +235,7 @@ func (my *Conn) connect() (err error) {
 	res := my.getResult(nil, nil)
 	if res == nil {
 		// Try old password
-		my.oldPasswd()
+		my.oldPasswd(my.passwd)
 		res = my.getResult(nil, nil)
 		if res == nil {
 			return AUTHENTICATION_ERROR
@@ -156,6 +243,10 @@ func (my *Conn) connect() (err error) {
 	}
 
 	// Execute all registered commands
+	return my.execInitCmds()
+}
+
+func (my *Conn) execInitCmds() (err error) {
 	for _, cmd := range my.init_cmds {
 		// Send command
 		my.sendCmd(_COM_QUERY, cmd)
@@ -186,7 +277,6 @@ func (my *Conn) connect() (err error) {
 			}
 		}
 	}
-
 	return
 }
 
@@ -286,12 +376,128 @@ func (my *Conn) Use(dbname string) (err error) {
 	return
 }
 
+// Change the user and the default database of the current connection without
+// reconnecting. The server closes all prepared statements and resets the
+// session state, so statement handlers obtained before this call are invalid
+// and registered commands are executed again. If the command succeeds, new
+// credentials are used by Reconnect.
+func (my *Conn) ChangeUser(user, passwd, db string) (err error) {
+	defer catchError(&err)
+
+	if my.net_conn == nil {
+		return NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return UNREADED_REPLY_ERROR
+	}
+
+	scrPasswd := encryptedPasswd(passwd, my.info.scramble)
+	if my.flags&_CLIENT_CONNECT_ATTRS != 0 {
+		my.sendCmd(_COM_CHANGE_USER, user, scrPasswd, db,
+			uint16(my.info.lang), encodeConnAttrs(my.conn_attrs))
+	} else {
+		my.sendCmd(_COM_CHANGE_USER, user, scrPasswd, db,
+			uint16(my.info.lang))
+	}
+	// Get server response
+	if my.getResult(nil, nil) == nil {
+		// Server requests old password (auth switch)
+		my.oldPasswd(passwd)
+		if my.getResult(nil, nil) == nil {
+			return AUTHENTICATION_ERROR
+		}
+	}
+	// Save new credentials if no errors
+	my.user = user
+	my.passwd = passwd
+	my.dbname = db
+	// Server deallocated all prepared statements
+	my.stmt_map = make(map[uint32]*Stmt)
+	my.stmt_cache.clear()
+
+	return my.execInitCmds()
+}
+
 func (my *Conn) getResponse() (res *Result) {
 	res = my.getResult(nil, nil)
 	if res == nil {
 		panic(BAD_RESULT_ERROR)
 	}
 	my.unreaded_reply = !res.StatusOnly()
+	my.last_res = res
+	if res.StatusOnly() && !res.MoreResults() {
+		my.replyEnd(res)
+	}
+	return
+}
+
+// Reads and discards all unreaded rows and results of the last command.
+func (my *Conn) discardReply() error {
+	for my.unreaded_reply {
+		res := my.last_res
+		if !res.eor_returned {
+			row := res.MakeRow()
+			for {
+				err := res.ScanRow(row)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if !res.MoreResults() {
+			my.unreaded_reply = false
+			break
+		}
+		if _, err := res.nextResult(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the session to the clean state, as it was just after connect:
+// rollbacks an active transaction, drops temporary tables, resets session and
+// user variables and closes all prepared statements (statement handlers
+// obtained before this call are invalid). Unreaded rows of the last command
+// are discarded. Registered commands are executed again.
+//
+// It uses COM_RESET_CONNECTION if the server supports it (MySQL 5.7.3 or
+// later), otherwise COM_CHANGE_USER with the current credentials.
+func (my *Conn) ResetSession() (err error) {
+	defer catchError(&err)
+
+	if my.net_conn == nil {
+		return NOT_CONN_ERROR
+	}
+	if err = my.discardReply(); err != nil {
+		return
+	}
+	if !my.no_reset_conn {
+		if err = my.resetConn(); err == nil {
+			return my.execInitCmds()
+		}
+		e, ok := err.(*mysql.Error)
+		if !ok || e.Code != mysql.ER_UNKNOWN_COM_ERROR {
+			return
+		}
+		my.no_reset_conn = true
+	}
+	return my.ChangeUser(my.user, my.passwd, my.dbname)
+}
+
+func (my *Conn) resetConn() (err error) {
+	defer catchError(&err)
+
+	// Send command
+	my.sendCmd(_COM_RESET_CONNECTION)
+	// Get server response
+	my.getResult(nil, nil)
+	// Server deallocated all prepared statements
+	my.stmt_map = make(map[uint32]*Stmt)
+	my.stmt_cache.clear()
 	return
 }
 
@@ -343,24 +549,55 @@ func (res *Result) ScanRow(row mysql.Row) error {
 	if row == nil {
 		return ROW_LENGTH_ERROR
 	}
-	if res.eor_returned {
-		return READ_AFTER_EOR_ERROR
+	if err := res.checkScan(); err != nil {
+		return err
 	}
 	if res.StatusOnly() {
 		// There is no fields in result (OK result)
 		res.eor_returned = true
 		return io.EOF
 	}
+	if len(row) != res.field_count {
+		// Row was made for another result (eg. before table was altered)
+		return ROW_LENGTH_ERROR
+	}
 	err := res.getRow(row)
 	if err == io.EOF {
-		res.eor_returned = true
-		if !res.MoreResults() {
-			res.my.unreaded_reply = false
-		}
+		return res.endOfRows()
 	}
 	return err
 }
 
+// Checks if next row can be read
+func (res *Result) checkScan() error {
+	if res.eor_returned {
+		return READ_AFTER_EOR_ERROR
+	}
+	if res.stream != nil && !res.stream.closed {
+		return STREAM_NOT_CLOSED_ERROR
+	}
+	return nil
+}
+
+// Called after EOF packet was read. Returns io.EOF or replyEnd error.
+func (res *Result) endOfRows() error {
+	res.eor_returned = true
+	if !res.MoreResults() {
+		res.my.unreaded_reply = false
+		if err := res.end(); err != nil {
+			return err
+		}
+	}
+	return io.EOF
+}
+
+func (res *Result) end() (err error) {
+	defer catchError(&err)
+
+	res.my.replyEnd(res)
+	return
+}
+
 // Like ScanRow but allocates memory for every row.
 // Returns nil row insted of io.EOF error.
 func (res *Result) GetRow() (mysql.Row, error) {
@@ -371,6 +608,8 @@ func (res *Result) nextResult() (next *Result, err error) {
 	defer catchError(&err)
 	if res.MoreResults() {
 		next = res.my.getResponse()
+		// All results of prepared statement use binary protocol
+		next.binary = res.binary
 	}
 	return
 }
@@ -438,6 +677,10 @@ func (my *Conn) Prepare(sql string) (mysql.Stmt, error) {
 		return nil, UNREADED_REPLY_ERROR
 	}
 
+	sql, names, err := mysql.ParseNamed(sql)
+	if err != nil {
+		return nil, err
+	}
 	stmt, err := my.prepare(sql)
 	if err != nil {
 		return nil, err
@@ -447,9 +690,98 @@ func (my *Conn) Prepare(sql string) (mysql.Stmt, error) {
 	// Save SQL for reconnect
 	stmt.sql = sql
 
+	if names != nil {
+		stmt.names = names
+		stmt.name_map = make(map[string][]int)
+		for ii, name := range names {
+			stmt.name_map[name] = append(stmt.name_map[name], ii)
+		}
+	}
 	return stmt, nil
 }
 
+// Returns addressable value for par (dereferenced pointer or copy)
+func addrValue(par interface{}) reflect.Value {
+	pval := reflect.ValueOf(par)
+	if pval.IsValid() {
+		if pval.Kind() == reflect.Ptr {
+			// Dereference pointer - this value i addressable
+			pval = pval.Elem()
+		} else {
+			// Make an addressable value
+			v := reflect.New(pval.Type()).Elem()
+			v.Set(pval)
+			pval = v
+		}
+	}
+	return pval
+}
+
+// Binds v to all parameters named name
+func (stmt *Stmt) bindName(name string, v reflect.Value) {
+	nums, ok := stmt.name_map[name]
+	if !ok {
+		panic(fmt.Errorf("unknown parameter name: %s", name))
+	}
+	for _, ii := range nums {
+		stmt.params[ii] = bindValue(v)
+	}
+}
+
+// Checks that all named parameters are binded
+func (stmt *Stmt) checkNamed() {
+	for ii, p := range stmt.params {
+		if p == nil {
+			panic(fmt.Errorf("missing value for parameter: %s", stmt.names[ii]))
+		}
+	}
+}
+
+func (stmt *Stmt) bindNamed(params map[string]interface{}) {
+	if stmt.names == nil {
+		panic(NO_NAMED_PARAMS_ERROR)
+	}
+	stmt.rebind = true
+	stmt.binded = false
+	clear(stmt.params)
+	for name, par := range params {
+		stmt.bindName(name, addrValue(par))
+	}
+	stmt.checkNamed()
+	stmt.binded = true
+}
+
+// Binds values from params map to the named parameters of the statement
+// (see mysql.ParseNamed). Returns an error if some parameter has no value or
+// params contains unknown name.
+func (stmt *Stmt) BindNamed(params map[string]interface{}) (err error) {
+	defer catchError(&err)
+	stmt.bindNamed(params)
+	return
+}
+
+// Binds fields of struct to the named parameters by `mysql:"name"` tags or
+// case-insensitive field names (see mysql.ScanStruct). Fields tagged
+// `mysql:"-"` are skipped.
+func (stmt *Stmt) bindStructNamed(pval reflect.Value) {
+	stmt.rebind = true
+	stmt.binded = false
+	clear(stmt.params)
+	lnames := make(map[string]string, len(stmt.name_map))
+	for name := range stmt.name_map {
+		lnames[strings.ToLower(name)] = name
+	}
+	for fname, index := range mysql.FieldIndexes(pval.Type()) {
+		name, ok := lnames[fname]
+		if !ok {
+			panic(fmt.Errorf("unknown parameter name: %s", fname))
+		}
+		stmt.bindName(name, pval.FieldByIndex(index))
+	}
+	stmt.checkNamed()
+	stmt.binded = true
+}
+
 // Bind input data for the parameter markers in the SQL statement that was
 // passed to Prepare.
 // 
@@ -457,12 +789,28 @@ func (my *Conn) Prepare(sql string) (mysql.Stmt, error) {
 // A struct field can by value or pointer to value. A parameter (slice element)
 // can be value, pointer to value or pointer to pointer to value.
 // Values may be of the folowind types: intXX, uintXX, floatXX, bool, []byte,
-// Blob, string, Time, Date, Time, Timestamp, Raw.
+// Blob, string, Time, Date, Time, Timestamp, Raw, types that implement
+// driver.Valuer (eg. sql.NullString) and types with registered encoder (see
+// mysql.RegisterEncoder). Valuers and registered types are converted during
+// every execution. io.Reader (eg. *os.File) can be binded to TEXT/BLOB
+// parameter: Run reads it to EOF and sends its data using SendLongData in
+// 64 kB chunks (smaller if server max_allowed_packet is smaller). If the
+// statement has to be reprepared (ER_NEED_REPREPARE), Run sends the data again
+// only if all readers implement io.Seeker, otherwise it returns the error.
+//
+// If the statement contains named parameters (see mysql.ParseNamed) you can
+// bind them using map[string]interface{} (see BindNamed) or a struct. Struct
+// fields are matched to the parameters by `mysql:"name"` tags or by
+// case-insensitive field names.
 func (stmt *Stmt) Bind(params ...interface{}) {
 	stmt.rebind = true
 
 	// Check for struct binding
 	if len(params) == 1 {
+		if m, ok := params[0].(map[string]interface{}); ok {
+			stmt.bindNamed(m)
+			return
+		}
 		pval := reflect.ValueOf(params[0])
 		kind := pval.Kind()
 		if kind == reflect.Ptr {
@@ -470,22 +818,21 @@ func (stmt *Stmt) Bind(params ...interface{}) {
 			pval = pval.Elem()
 			kind = pval.Kind()
 		}
-		typ := pval.Type()
-		if kind == reflect.Struct &&
-			typ != timeType &&
-			typ != dateType &&
-			typ != timestampType &&
-			typ != rawType {
+		if kind == reflect.Struct && !isValueType(pval.Type()) {
 			// We have struct to bind
-			if pval.NumField() != stmt.param_count {
-				panic(BIND_COUNT_ERROR)
-			}
 			if !pval.CanAddr() {
 				// Make an addressable structure
 				v := reflect.New(pval.Type()).Elem()
 				v.Set(pval)
 				pval = v
 			}
+			if stmt.names != nil {
+				stmt.bindStructNamed(pval)
+				return
+			}
+			if pval.NumField() != stmt.param_count {
+				panic(BIND_COUNT_ERROR)
+			}
 			for ii := 0; ii < stmt.param_count; ii++ {
 				stmt.params[ii] = bindValue(pval.Field(ii))
 			}
@@ -501,19 +848,7 @@ func (stmt *Stmt) Bind(params ...interface{}) {
 		panic(BIND_COUNT_ERROR)
 	}
 	for ii, par := range params {
-		pval := reflect.ValueOf(par)
-		if pval.IsValid() {
-			if pval.Kind() == reflect.Ptr {
-				// Dereference pointer - this value i addressable
-				pval = pval.Elem()
-			} else {
-				// Make an addressable value
-				v := reflect.New(pval.Type()).Elem()
-				v.Set(pval)
-				pval = v
-			}
-		}
-		stmt.params[ii] = bindValue(pval)
+		stmt.params[ii] = bindValue(addrValue(par))
 	}
 	stmt.binded = true
 }
@@ -532,6 +867,9 @@ func (stmt *Stmt) ResetParams() {
 func (stmt *Stmt) Run(params ...interface{}) (res mysql.Result, err error) {
 	defer catchError(&err)
 
+	if stmt.evicted {
+		return nil, STMT_EVICTED_ERROR
+	}
 	if stmt.my.net_conn == nil {
 		return nil, NOT_CONN_ERROR
 	}
@@ -546,11 +884,35 @@ func (stmt *Stmt) Run(params ...interface{}) (res mysql.Result, err error) {
 		panic(BIND_COUNT_ERROR)
 	}
 
-	// Send EXEC command with binded parameters
-	stmt.sendCmdExec()
-	// Get response
-	r := stmt.my.getResponse()
+	// Data of readers have to be sent again if statement is reprepared
+	offs, rewindable := stmt.readerOffsets()
+	if err = stmt.sendReaders(); err != nil {
+		// Discard data that was sent before the error
+		stmt.Reset()
+		return nil, err
+	}
+	r, err := stmt.exec()
+	if e, ok := err.(*mysql.Error); ok && e.Code == mysql.ER_NEED_REPREPARE {
+		// Table structure was changed, prepare statement again and retry
+		stmt.reprepare()
+		if !rewindable {
+			// Readers were consumed by the old statement
+			return nil, err
+		}
+		if offs != nil {
+			stmt.rewindReaders(offs)
+			if err = stmt.sendReaders(); err != nil {
+				stmt.Reset()
+				return nil, err
+			}
+		}
+		r, err = stmt.exec()
+	}
+	if err != nil {
+		return nil, err
+	}
 	r.binary = true
+	stmt.updateFields(r)
 	res = r
 	return
 }
@@ -558,8 +920,11 @@ func (stmt *Stmt) Run(params ...interface{}) (res mysql.Result, err error) {
 // Destroy statement on server side. Client side handler is invalid after this
 // command.
 func (stmt *Stmt) Delete() (err error) {
-	defer catchError(&err)
-
+	if stmt.evicted {
+		// Statement cache destroyed it on server side
+		*stmt = Stmt{}
+		return
+	}
 	if stmt.my.net_conn == nil {
 		return NOT_CONN_ERROR
 	}
@@ -567,14 +932,20 @@ func (stmt *Stmt) Delete() (err error) {
 		return UNREADED_REPLY_ERROR
 	}
 
-	// Allways delete statement on client side, even if
-	// the command return an error.
+	// Invalidate handler
 	defer func() {
-		// Delete statement from stmt_map
-		delete(stmt.my.stmt_map, stmt.id)
-		// Invalidate handler
 		*stmt = Stmt{}
 	}()
+	return stmt.close()
+}
+
+// Sends COM_STMT_CLOSE and deletes statement from stmt_map.
+func (stmt *Stmt) close() (err error) {
+	defer catchError(&err)
+
+	// Allways delete statement on client side, even if
+	// the command return an error.
+	defer delete(stmt.my.stmt_map, stmt.id)
 
 	// Send command
 	stmt.my.sendCmd(_COM_STMT_CLOSE, stmt.id)
@@ -586,6 +957,9 @@ func (stmt *Stmt) Delete() (err error) {
 func (stmt *Stmt) Reset() (err error) {
 	defer catchError(&err)
 
+	if stmt.evicted {
+		return STMT_EVICTED_ERROR
+	}
 	if stmt.my.net_conn == nil {
 		return NOT_CONN_ERROR
 	}
@@ -625,6 +999,9 @@ func (stmt *Stmt) Reset() (err error) {
 func (stmt *Stmt) SendLongData(pnum int, data interface{}, pkt_size int) (err error) {
 	defer catchError(&err)
 
+	if stmt.evicted {
+		return STMT_EVICTED_ERROR
+	}
 	if stmt.my.net_conn == nil {
 		return NOT_CONN_ERROR
 	}
@@ -721,6 +1098,11 @@ func (stmt *Stmt) ExecFirst(params ...interface{}) (mysql.Row, mysql.Result, err
 	return mysql.ExecFirst(stmt, params...)
 }
 
+// See mysql.ExecCall
+func (stmt *Stmt) ExecCall(params ...interface{}) (*mysql.CallResult, error) {
+	return mysql.ExecCall(stmt, params...)
+}
+
 // See mysql.ExecLast
 func (stmt *Stmt) ExecLast(params ...interface{}) (mysql.Row, mysql.Result, error) {
 	return mysql.ExecLast(stmt, params...)
@@ -746,6 +1128,21 @@ func (res *Result) GetRows() ([]mysql.Row, error) {
 	return mysql.GetRows(res)
 }
 
+// See mysql.All
+func (res *Result) All() iter.Seq2[mysql.Row, error] {
+	return mysql.All(res)
+}
+
+// See mysql.Results
+func (res *Result) Results() iter.Seq2[mysql.Result, error] {
+	return mysql.Results(res)
+}
+
+// See mysql.ScanStruct
+func (res *Result) ScanStruct(row mysql.Row, dst interface{}) error {
+	return mysql.ScanStruct(res, row, dst)
+}
+
 // Escapes special characters in the txt, so it is safe to place returned string
 // to Query method.
 func (my *Conn) EscapeString(txt string) string {
@@ -2,11 +2,15 @@ package native
 
 import (
 	"bytes"
+	"database/sql"
 	"fmt"
 	"github.com/ziutek/mymysql/mysql"
+	"github.com/ziutek/mymysql/mysql/geom"
+	"io"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -181,6 +185,90 @@ func TestPing(t *testing.T) {
 	myClose(t)
 }
 
+func TestChangeUser(t *testing.T) {
+	myConnect(t, false, 0)
+	_, err := my.Prepare("select 1")
+	checkErr(t, err, nil)
+	checkErr(t, my.ChangeUser(user, passwd, dbname), nil)
+	if len(my.(*Conn).stmt_map) != 0 {
+		t.Fatal("Prepared statements weren't forgotten after ChangeUser")
+	}
+	row, _, err := my.QueryFirst("select database()")
+	checkErr(t, err, nil)
+	if row.Str(0) != dbname {
+		t.Fatalf("Bad database after ChangeUser: %s", row.Str(0))
+	}
+	checkErr(t, my.Reconnect(), nil)
+	row, _, err = my.QueryFirst("select database()")
+	checkErr(t, err, nil)
+	if row.Str(0) != dbname {
+		t.Fatalf("Bad database after Reconnect: %s", row.Str(0))
+	}
+	myClose(t)
+}
+
+func TestResetSession(t *testing.T) {
+	myConnect(t, true, 0)
+	checkResult(t, query("set @a = 1"), cmdOK(0, false, true))
+	checkErr(t, my.SetMultiStatements(true), nil)
+	_, err := my.Start("select 1; select 2")
+	checkErr(t, err, nil)
+	checkErr(t, my.ResetSession(), nil)
+	if len(my.(*Conn).stmt_map) != 0 {
+		t.Fatal("Prepared statements weren't forgotten after ResetSession")
+	}
+	row, _, err := my.QueryFirst("select @a")
+	checkErr(t, err, nil)
+	if row[0] != nil {
+		t.Fatalf("User variable wasn't reset: %v", row[0])
+	}
+	myClose(t)
+}
+
+func TestStatistics(t *testing.T) {
+	myConnect(t, false, 0)
+	st, err := my.Statistics()
+	checkErr(t, err, nil)
+	if st.Threads < 1 || st.Uptime <= 0 {
+		t.Fatalf("Bad statistics: %+v", st)
+	}
+	rows, res, err := my.ProcessList()
+	checkErr(t, err, nil)
+	found := false
+	for _, row := range rows {
+		if uint32(row.Uint64(res.Map("Id"))) == my.ThreadId() {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("Current thread not found in process list")
+	}
+	myClose(t)
+}
+
+func TestWarnings(t *testing.T) {
+	myConnect(t, false, 0)
+	my.SetAutoWarnings(true)
+	rows, res, err := my.Query("select cast('1a' as signed)")
+	checkErr(t, err, nil)
+	if len(rows) != 1 || res.WarnCount() != 1 {
+		t.Fatalf("Bad result: rows=%v warn_count=%d", rows, res.WarnCount())
+	}
+	warns := res.Warnings()
+	if len(warns) != 1 || warns[0].Code != mysql.ER_TRUNCATED_WRONG_VALUE {
+		t.Fatalf("Bad warnings: %v", warns)
+	}
+
+	my.SetStrictWarnings(mysql.TruncationWarnings...)
+	_, _, err = my.Query("select cast('1a' as signed)")
+	if e, ok := err.(*mysql.Error); !ok || e.Code != mysql.ER_TRUNCATED_WRONG_VALUE {
+		t.Fatalf("Expected truncation error, got: %v", err)
+	}
+	// Connection should be usable after the error
+	checkErr(t, my.Ping(), nil)
+	myClose(t)
+}
+
 func TestQuery(t *testing.T) {
 	myConnect(t, true, 0)
 	query("drop table T") // Drop test table if exists
@@ -518,6 +606,454 @@ func TestBindStruct(t *testing.T) {
 	myClose(t)
 }
 
+func TestNamedParams(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (id int primary key, txt varchar(20), b bool)"),
+		cmdOK(0, false, true),
+	)
+
+	ins, err := my.Prepare("insert T values (:id, :txt, :b)")
+	checkErr(t, err, nil)
+	if ins.NumParam() != 3 {
+		t.Fatal("Bad number of parameters:", ins.NumParam())
+	}
+	// User variables aren't parameters
+	set, err := my.Prepare("set @a = 1")
+	checkErr(t, err, nil)
+	if set.NumParam() != 0 {
+		t.Fatal("Bad number of parameters:", set.NumParam())
+	}
+	checkErr(t, set.Delete(), nil)
+	sel, err := my.Prepare("select txt, b from T where id = :id or id = :id")
+	checkErr(t, err, nil)
+
+	var rre RowsResErr
+	rre.res, rre.err = ins.Run(map[string]interface{}{
+		"id": 1, "txt": "jeden", "b": true,
+	})
+	checkResult(t, &rre, cmdOK(1, true, false))
+
+	s := struct {
+		Id   int
+		Text string `mysql:"txt"`
+		B    bool
+	}{2, "dwa", false}
+	rre.res, rre.err = ins.Run(&s)
+	checkResult(t, &rre, cmdOK(1, true, false))
+
+	checkErr(t, sel.BindNamed(map[string]interface{}{"id": 2}), nil)
+	rows, _, err := sel.Exec()
+	checkErr(t, err, nil)
+	if len(rows) != 1 || rows[0].Str(0) != s.Text || rows[0].Bool(1) != s.B {
+		t.Fatal("selected data don't match inserted data")
+	}
+	if sel.BindNamed(map[string]interface{}{"id": 2, "x": 1}) == nil {
+		t.Fatal("No error for unknown parameter")
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestValuerScanner(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (id int, s varchar(20), m decimal(10,2))"),
+		cmdOK(0, false, true),
+	)
+
+	ins, err := my.Prepare("insert T values (?, ?, ?)")
+	checkErr(t, err, nil)
+	var (
+		id  sql.NullInt64
+		str sql.NullString
+		m   testMoney
+	)
+	ins.Bind(&id, &str, &m)
+	id = sql.NullInt64{Int64: 1, Valid: true}
+	str, m = sql.NullString{String: "a", Valid: true}, 123
+	_, err = ins.Run()
+	checkErr(t, err, nil)
+	id.Int64, str.Valid, m = 2, false, 4567
+	_, err = ins.Run()
+	checkErr(t, err, nil)
+
+	var rows []struct {
+		Id sql.NullInt64
+		S  sql.NullString
+		M  sql.NullFloat64
+	}
+	_, err = mysql.QueryInto(my, &rows, "select * from T order by id")
+	checkErr(t, err, nil)
+	if len(rows) != 2 || rows[0].S.String != "a" || rows[1].S.Valid ||
+		rows[0].M.Float64 != 1.23 || rows[1].M.Float64 != 45.67 {
+		t.Fatalf("selected data don't match inserted data: %+v", rows)
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestPrepareCached(t *testing.T) {
+	myConnect(t, true, 0)
+	c := my.(*Conn)
+	checkErr(t, my.SetStmtCacheSize(2), nil)
+
+	s1, err := my.PrepareCached("select 1")
+	checkErr(t, err, nil)
+	s2, err := my.PrepareCached("select 2")
+	checkErr(t, err, nil)
+	s, err := my.PrepareCached("select 1")
+	checkErr(t, err, nil)
+	if s != s1 {
+		t.Fatal("Statement not found in the cache")
+	}
+	// Evicts "select 2"
+	_, err = my.PrepareCached("select 3")
+	checkErr(t, err, nil)
+	exp := mysql.StmtCacheStats{Hits: 1, Misses: 3, Evictions: 1, Len: 2}
+	if st := my.StmtCacheStats(); st != exp || len(c.stmt_map) != 2 {
+		t.Fatalf("Bad stats: %+v, stmt_map: %d", st, len(c.stmt_map))
+	}
+	// Evicted statement can be still held but can't be executed
+	_, _, err = s2.Exec()
+	checkErr(t, err, STMT_EVICTED_ERROR)
+
+	// Cached statements survive reconnect
+	checkErr(t, my.Reconnect(), nil)
+	s, err = my.PrepareCached("select 1")
+	checkErr(t, err, nil)
+	row, _, err := s.ExecFirst()
+	checkErr(t, err, nil)
+	if s != s1 || row.Int(0) != 1 {
+		t.Fatal("Bad cached statement after reconnect")
+	}
+
+	checkErr(t, my.SetStmtCacheSize(0), nil)
+	if my.StmtCacheStats().Len != 0 || len(c.stmt_map) != 0 {
+		t.Fatal("Statements not deleted after cache resize")
+	}
+	myClose(t)
+}
+
+func TestReprepare(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t, query("create table T (a int)"), cmdOK(0, false, true))
+	checkResult(t, query("insert T values (1)"), cmdOK(1, false, true))
+
+	sel, err := my.Prepare("select * from T")
+	checkErr(t, err, nil)
+	res, err := sel.Run()
+	checkErr(t, err, nil)
+	old_row := res.MakeRow()
+	checkErr(t, mysql.End(res), nil)
+
+	checkResult(t, query("alter table T add b varchar(8) default 'x'"),
+		cmdOK(0, false, true))
+
+	res, err = sel.Run()
+	checkErr(t, err, nil)
+	if sel.NumField() != 2 || sel.Map("b") != 1 {
+		t.Fatal("Statement fields not updated:", sel.NumField())
+	}
+	checkErr(t, res.ScanRow(old_row), ROW_LENGTH_ERROR)
+	row, err := mysql.GetLastRow(res)
+	checkErr(t, err, nil)
+	if row.Int(0) != 1 || row.Str(1) != "x" {
+		t.Fatal("Bad row after table alter:", row)
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestExecCall(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop procedure if exists P")
+	checkResult(t, query(
+		"create procedure P(in n int, out total int, inout x int) begin"+
+			" select n; select n+1; set total = n*2; set x = x+1; end",
+	), cmdOK(0, false, true))
+
+	call, err := my.Prepare("call P(?, ?, ?)")
+	checkErr(t, err, nil)
+	cr, err := call.ExecCall(10, nil, 5)
+	checkErr(t, err, nil)
+	if len(cr.Sets) != 2 || cr.Sets[0].Rows[0].Int(0) != 10 ||
+		cr.Sets[1].Rows[0].Int(0) != 11 {
+		t.Fatal("Bad result sets:", cr.Sets)
+	}
+	if cr.Out == nil || !cr.OutRes.IsOutParams() ||
+		cr.Out.Int(cr.OutRes.Map("total")) != 20 ||
+		cr.Out.Int(cr.OutRes.Map("x")) != 6 {
+		t.Fatal("Bad OUT parameters:", cr.Out)
+	}
+	if cr.Status == nil || my.(*Conn).unreaded_reply {
+		t.Fatal("Call result not read completely")
+	}
+
+	checkResult(t, query("drop procedure P"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestBindReader(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t, query("create table T (id int, b mediumblob)"),
+		cmdOK(0, false, true))
+
+	ins, err := my.Prepare("insert T values (?, ?)")
+	checkErr(t, err, nil)
+	data := bytes.Repeat([]byte("0123456789abcdef"), 10000) // 3 chunks
+	_, _, err = ins.Exec(1, bytes.NewReader(data))
+	checkErr(t, err, nil)
+	_, _, err = ins.Exec(2, strings.NewReader(""))
+	checkErr(t, err, nil)
+	_, _, err = ins.Exec(3, errReader{})
+	checkErr(t, err, io.ErrClosedPipe)
+
+	rows, _, err := my.Query("select b from T order by id")
+	checkErr(t, err, nil)
+	if len(rows) != 2 || !bytes.Equal(rows[0].Bin(0), data) ||
+		rows[1][0] == nil || len(rows[1].Bin(0)) != 0 {
+		t.Fatal("selected data don't match inserted data")
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestTypedText(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (a tinyint unsigned, b smallint, c bigint, "+
+			"d float, e double, f decimal(10,2), g date, h datetime, i time, "+
+			"j year, k varchar(10), l bit(8))"),
+		cmdOK(0, false, true),
+	)
+	_, _, err := my.Query("insert T values (200, -5, -9000000000, 1.5, " +
+		"-2.25, 12.34, '2013-07-14', '2013-07-14 15:04:05', '-12:30:00', " +
+		"2013, 'abc', b'101'), (null, null, null, null, null, null, null, " +
+		"null, null, null, null, null)")
+	checkErr(t, err, nil)
+
+	my.SetTypedText(true)
+	defer my.SetTypedText(false)
+	text_rows, _, err := my.Query("select * from T")
+	checkErr(t, err, nil)
+	sel, err := my.Prepare("select * from T")
+	checkErr(t, err, nil)
+	bin_rows, _, err := sel.Exec()
+	checkErr(t, err, nil)
+	if !reflect.DeepEqual(text_rows, bin_rows) {
+		t.Fatalf("Text rows %v don't match binary rows %v", text_rows,
+			bin_rows)
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestBitEnumSet(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (b bit(12), e enum('a','it''s'), "+
+			"s set('x','y','z'), y year)"),
+		cmdOK(0, false, true),
+	)
+	ins, err := my.Prepare("insert T values (?, ?, ?, ?)")
+	checkErr(t, err, nil)
+	_, err = ins.Run(mysql.Bit(0x105), "it's", mysql.Set{"x", "z"}, 2013)
+	checkErr(t, err, nil)
+	_, err = ins.Run(nil, nil, mysql.Set{}, nil)
+	checkErr(t, err, nil)
+	if _, err = ins.Run(nil, nil, mysql.Set{"x,y"}, nil); err == nil {
+		t.Fatal("No error for SET member with comma")
+	}
+
+	text_rows, _, err := my.Query("select * from T")
+	checkErr(t, err, nil)
+	sel, err := my.Prepare("select * from T")
+	checkErr(t, err, nil)
+	bin_rows, _, err := sel.Exec()
+	checkErr(t, err, nil)
+	for _, rows := range [][]mysql.Row{text_rows, bin_rows} {
+		if len(rows) != 2 {
+			t.Fatal("Bad number of rows:", len(rows))
+		}
+		r := rows[0]
+		if r.Bit(0) != 0x105 || r.Str(1) != "it's" ||
+			!reflect.DeepEqual(r.Set(2), []string{"x", "z"}) ||
+			r.Year(3) != 2013 {
+			t.Fatalf("Bad row: %v", r)
+		}
+		r = rows[1]
+		if r.Bit(0) != 0 || r.Set(2) == nil || len(r.Set(2)) != 0 ||
+			r.Year(3) != 0 {
+			t.Fatalf("Bad row: %v", r)
+		}
+	}
+
+	ev, err := mysql.LoadEnumValues(my, "T", "e")
+	checkErr(t, err, nil)
+	if !reflect.DeepEqual(ev, mysql.EnumValues{"a", "it's"}) {
+		t.Fatalf("Bad enum values: %q", ev)
+	}
+	ev, err = mysql.LoadEnumValues(my, dbname+".T", "s")
+	checkErr(t, err, nil)
+	if ev.CheckSet(text_rows[0].Set(2)) != nil || ev.Check("w") == nil {
+		t.Fatalf("Bad set values: %q", ev)
+	}
+	if _, err = mysql.LoadEnumValues(my, "T", "x"); err == nil {
+		t.Fatal("No error for nonexistent column")
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestGeometry(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (id int, g geometry)"),
+		cmdOK(0, false, true),
+	)
+	zone := geom.Polygon{{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 0, Y: 0},
+	}}
+	pts := geom.MultiPoint{{X: 1, Y: 2}, {X: 3, Y: 4}}
+	_, _, err := my.Query(
+		"insert T values (1, ST_GeomFromText('%s', 4326))", zone,
+	)
+	checkErr(t, err, nil)
+	ins, err := my.Prepare("insert T values (?, ?)")
+	checkErr(t, err, nil)
+	_, err = ins.Run(2, geom.Value{SRID: 4326, Geometry: zone})
+	checkErr(t, err, nil)
+	_, err = ins.Run(3, pts)
+	checkErr(t, err, nil)
+	_, err = ins.Run(4, nil)
+	checkErr(t, err, nil)
+
+	exp := []geom.Value{
+		{SRID: 4326, Geometry: zone}, {SRID: 4326, Geometry: zone},
+		{SRID: 0, Geometry: pts}, {},
+	}
+	text_rows, _, err := my.Query("select g from T order by id")
+	checkErr(t, err, nil)
+	sel, err := my.Prepare("select g from T order by id")
+	checkErr(t, err, nil)
+	bin_rows, _, err := sel.Exec()
+	checkErr(t, err, nil)
+	for _, rows := range [][]mysql.Row{text_rows, bin_rows} {
+		if len(rows) != len(exp) {
+			t.Fatal("Bad number of rows:", len(rows))
+		}
+		for ii, row := range rows {
+			if v := row.Geometry(0); !reflect.DeepEqual(v, exp[ii]) {
+				t.Fatalf("Row %d: %v != %v", ii, v, exp[ii])
+			}
+		}
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestPipelineExec(t *testing.T) {
+	myConnect(t, true, 0)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (id int primary key, s varchar(20))"),
+		cmdOK(0, false, true),
+	)
+	ins, err := my.Prepare("insert T values (?, ?)")
+	checkErr(t, err, nil)
+
+	p := my.Pipeline()
+	for ii := 0; ii < 10; ii++ {
+		p.Exec(ins, ii, fmt.Sprint("s", ii))
+	}
+	p.Exec(ins, 5, "dup")
+	p.Query("insert T values (%d, '%s')", 10, "s10")
+	p.Query("select count(*) from T")
+	results, err := p.Run()
+	checkErr(t, err, nil)
+	for ii, r := range results[:10] {
+		if r.Err != nil || r.Res.AffectedRows() != 1 {
+			t.Fatalf("%d: %v", ii, r.Err)
+		}
+	}
+	if !mysql.IsDuplicateKey(results[10].Err) {
+		t.Fatal("Expected duplicate key error, got:", results[10].Err)
+	}
+	if r := results[12]; r.Err != nil || r.Rows[0].Int(0) != 11 {
+		t.Fatalf("Bad count: %v %v", r.Rows, r.Err)
+	}
+	// Connection is in sync after the pipeline
+	row, _, err := my.QueryFirst("select s from T where id=10")
+	checkErr(t, err, nil)
+	if row.Str(0) != "s10" {
+		t.Fatal("Bad row:", row)
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
+func TestBulkInserter(t *testing.T) {
+	myConnect(t, true, 1024)
+	query("drop table T") // Drop test table if exists
+	checkResult(t,
+		query("create table T (id int auto_increment primary key, "+
+			"s varchar(40) unique, n int)"),
+		cmdOK(0, false, true),
+	)
+	bi, err := mysql.NewBulkInserter(my, "T", "s", "n")
+	checkErr(t, err, nil)
+	for ii := 0; ii < 100; ii++ {
+		checkErr(t, bi.Add(fmt.Sprintf("it's row %d", ii), ii), nil)
+	}
+	checkErr(t, bi.Flush(), nil)
+	ids := bi.InsertIds()
+	if bi.AffectedRows() != 100 || len(ids) < 2 || ids[0] != 1 {
+		t.Fatalf("Bad result: %d %v", bi.AffectedRows(), ids)
+	}
+
+	// Update duplicates
+	bi, err = mysql.NewBulkInserter(my, "T", "s", "n")
+	checkErr(t, err, nil)
+	checkErr(t, bi.SetOnDuplicate("n=n+VALUES(n)"), nil)
+	checkErr(t, bi.Insert(
+		[]interface{}{"it's row 1", 10},
+		[]interface{}{"new row", 1},
+	), nil)
+	if bi.AffectedRows() != 3 {
+		t.Fatal("Bad number of affected rows:", bi.AffectedRows())
+	}
+	row, _, err := my.QueryFirst("select n from T where s='it''s row 1'")
+	checkErr(t, err, nil)
+	if row.Int(0) != 11 {
+		t.Fatal("Bad updated value:", row.Int(0))
+	}
+
+	checkResult(t, query("drop table T"), cmdOK(0, false, true))
+	myClose(t)
+}
+
 func TestDate(t *testing.T) {
 	myConnect(t, true, 0)
 	query("drop table D") // Drop test table if exists
@@ -938,6 +1474,7 @@ func TestMultipleResults(t *testing.T) {
 	checkResult(t, query("insert M values (2, '%s')", str[2]),
 		cmdOK(1, false, true))
 
+	checkErr(t, my.SetMultiStatements(true), nil)
 	res, err := my.Start("select id from M; select str from M")
 	checkErr(t, err, nil)
 
@@ -964,6 +1501,35 @@ func TestMultipleResults(t *testing.T) {
 		}
 	}
 
+	// Iterators
+	res, err = my.Start("select id from M; select str from M")
+	checkErr(t, err, nil)
+	n := 0
+	for r, err := range res.Results() {
+		checkErr(t, err, nil)
+		for row, err := range r.All() {
+			checkErr(t, err, nil)
+			if n < 3 && row.Int(0) != n || n >= 3 && row.Str(0) != str[n-3] {
+				t.Fatal("Bad result")
+			}
+			n++
+		}
+	}
+	if n != 6 {
+		t.Fatal("Bad number of rows:", n)
+	}
+
+	// Break should discard remaining rows and results
+	res, err = my.Start("select id from M; select str from M")
+	checkErr(t, err, nil)
+	for _, err := range res.All() {
+		checkErr(t, err, nil)
+		break
+	}
+	if my.(*Conn).unreaded_reply {
+		t.Fatal("Reply not discarded after break")
+	}
+
 	checkResult(t, query("drop table M"), cmdOK(0, false, true))
 	myClose(t)
 }
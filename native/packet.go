@@ -11,10 +11,27 @@ type pktReader struct {
 	seq    *byte
 	remain int
 	last   bool
+	hdr    [4]byte
 }
 
+// Returns reader for next packet. There is only one packet reader per
+// connection, so the reader returned by previous call becomes invalid.
 func (my *Conn) newPktReader() *pktReader {
-	return &pktReader{rd: my.rd, seq: &my.seq}
+	my.pr = pktReader{rd: my.rd, seq: &my.seq}
+	return &my.pr
+}
+
+// Reads header of next packet
+func (pr *pktReader) readHeader() {
+	readFull(pr.rd, pr.hdr[:])
+	pr.remain = int(DecodeU24(pr.hdr[:3]))
+	// Chceck sequence number
+	if *pr.seq != pr.hdr[3] {
+		panic(SEQ_ERROR)
+	}
+	*pr.seq++
+	// Last packet?
+	pr.last = (pr.remain != 0xffffff)
 }
 
 func (pr *pktReader) Read(buf []byte) (num int, err error) {
@@ -29,16 +46,7 @@ func (pr *pktReader) Read(buf []byte) (num int, err error) {
 			// No more packets
 			return 0, io.EOF
 		}
-		// Read next packet header
-		pr.remain = int(readU24(pr.rd))
-		seq := readByte(pr.rd)
-		// Chceck sequence number
-		if *pr.seq != seq {
-			return 0, SEQ_ERROR
-		}
-		*pr.seq++
-		// Last packet?
-		pr.last = (pr.remain != 0xffffff)
+		pr.readHeader()
 	}
 	// Reading data
 	if len(buf) <= pr.remain {
@@ -50,6 +58,45 @@ func (pr *pktReader) Read(buf []byte) (num int, err error) {
 	return
 }
 
+// Like readByte(pr) but doesn't allocate.
+func (pr *pktReader) readByte() byte {
+	for pr.remain == 0 {
+		if pr.last {
+			panic(io.ErrUnexpectedEOF)
+		}
+		pr.readHeader()
+	}
+	b, err := pr.rd.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		panic(err)
+	}
+	pr.remain--
+	return b
+}
+
+func (pr *pktReader) readU16() uint16 {
+	return uint16(pr.readByte()) | uint16(pr.readByte())<<8
+}
+
+// Appends the rest of packet data (from all remaining packets) to buf.
+func (pr *pktReader) appendAll(buf []byte) []byte {
+	for {
+		if pr.remain != 0 {
+			nn := len(buf)
+			buf = append(buf, make([]byte, pr.remain)...)
+			readFull(pr.rd, buf[nn:])
+			pr.remain = 0
+		}
+		if pr.last {
+			return buf
+		}
+		pr.readHeader()
+	}
+}
+
 func (pr *pktReader) readAll() (buf []byte) {
 	buf = make([]byte, pr.remain)
 	nn := 0
@@ -90,10 +137,13 @@ type pktWriter struct {
 	remain   int
 	to_write int
 	last     bool
+	no_flush bool
 }
 
 func (my *Conn) newPktWriter(to_write int) *pktWriter {
-	return &pktWriter{wr: my.wr, seq: &my.seq, to_write: to_write}
+	return &pktWriter{
+		wr: my.wr, seq: &my.seq, to_write: to_write, no_flush: my.no_flush,
+	}
 }
 
 /*func writePktHeader(wr io.Writer, seq byte, pay_len int) {
@@ -147,8 +197,10 @@ func (pw *pktWriter) Write(buf []byte) (num int, err error) {
 			// Update sequence number
 			*pw.seq++
 		}
-		// Flush bufio buffers
-		err = pw.wr.Flush()
+		if !pw.no_flush {
+			// Flush bufio buffers
+			err = pw.wr.Flush()
+		}
 	}
 	return
 }
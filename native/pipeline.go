@@ -0,0 +1,250 @@
+package native
+
+import (
+	"fmt"
+	"github.com/ziutek/mymysql/mysql"
+)
+
+// Command queued in pipeline
+type pipeCmd struct {
+	sql    string
+	stmt   *Stmt
+	params []interface{}
+	err    error // Error that prevented sending the command
+
+	sent  bool
+	reset bool // COM_STMT_RESET was sent instead of the command
+	seq   byte // Sequence number after the command was written
+}
+
+// Pipeline of commands (see mysql.Pipeline).
+//
+// Commands are written to the connection one after another and flushed
+// together. Because the server sends responses while the client is still
+// writing, don't queue commands that return big result sets together with
+// many other commands: the client reads nothing until all commands are
+// written.
+//
+// Warnings of pipelined commands aren't retrieved automatically (see
+// SetAutoWarnings), only their count is available. A statement that the
+// server reports as needing reprepare isn't executed: it is prepared again
+// and its result contains the ER_NEED_REPREPARE error, so the command can be
+// retried.
+type Pipeline struct {
+	my   *Conn
+	cmds []pipeCmd
+}
+
+// Returns a new, empty pipeline.
+func (my *Conn) Pipeline() mysql.Pipeline {
+	return &Pipeline{my: my}
+}
+
+func (p *Pipeline) Query(sql string, params ...interface{}) {
+	if len(params) != 0 {
+		sql = fmt.Sprintf(sql, params...)
+	}
+	p.cmds = append(p.cmds, pipeCmd{sql: sql})
+}
+
+func (p *Pipeline) Exec(st mysql.Stmt, params ...interface{}) {
+	cmd := pipeCmd{params: params}
+	if stmt, ok := st.(*Stmt); ok && stmt.my == p.my {
+		cmd.stmt = stmt
+	} else {
+		cmd.err = PIPELINE_STMT_ERROR
+	}
+	p.cmds = append(p.cmds, cmd)
+}
+
+func (p *Pipeline) Len() int {
+	return len(p.cmds)
+}
+
+func (p *Pipeline) Run() ([]mysql.PipelineResult, error) {
+	my := p.my
+	if my.net_conn == nil {
+		return nil, NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return nil, UNREADED_REPLY_ERROR
+	}
+	cmds := p.cmds
+	p.cmds = nil
+
+	// Chunks of io.Reader parameters can't be longer than max_allowed_packet
+	// and it can't be queried while commands are written.
+	for ii := range cmds {
+		if cmds[ii].stmt != nil {
+			if err := my.readMaxAllowed(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if err := p.send(cmds); err != nil {
+		return nil, err
+	}
+	results := make([]mysql.PipelineResult, len(cmds))
+	if err := p.receive(cmds, results); err != nil {
+		return nil, err
+	}
+	// Statements that have to be prepared again weren't executed. Prepare
+	// them, so the caller can retry these commands.
+	reprepared := make(map[*Stmt]bool)
+	for ii := range cmds {
+		stmt := cmds[ii].stmt
+		e, ok := results[ii].Err.(*mysql.Error)
+		if !ok || e.Code != mysql.ER_NEED_REPREPARE || stmt == nil ||
+			reprepared[stmt] {
+			continue
+		}
+		reprepared[stmt] = true
+		if err := stmt.tryReprepare(); err != nil {
+			if !isCmdError(err) {
+				return nil, err
+			}
+			results[ii].Err = err
+		}
+	}
+	return results, nil
+}
+
+// Prepares stmt again. Returns an error instead of panicking.
+func (stmt *Stmt) tryReprepare() (err error) {
+	defer catchError(&err)
+
+	stmt.reprepare()
+	return
+}
+
+// Binds params of stmt and converts them. Returns an error if stmt can't be
+// executed (nothing was written).
+func (stmt *Stmt) bindExec(params []interface{}) (err error) {
+	defer catchError(&err)
+
+	if stmt.evicted {
+		return STMT_EVICTED_ERROR
+	}
+	if len(params) != 0 {
+		stmt.Bind(params...)
+	} else if stmt.param_count != 0 && !stmt.binded {
+		return BIND_COUNT_ERROR
+	}
+	stmt.convParams()
+	return
+}
+
+// Writes all commands that can be sent and flushes them.
+func (p *Pipeline) send(cmds []pipeCmd) (err error) {
+	defer catchError(&err)
+
+	my := p.my
+	my.no_flush = true
+	defer func() {
+		my.no_flush = false
+	}()
+	for ii := range cmds {
+		cmd := &cmds[ii]
+		switch {
+		case cmd.err != nil:
+			continue
+		case cmd.stmt == nil:
+			my.sendCmd(_COM_QUERY, cmd.sql)
+		default:
+			if cmd.err = cmd.stmt.bindExec(cmd.params); cmd.err != nil {
+				continue
+			}
+			if cmd.err = cmd.stmt.sendReaders(); cmd.err != nil {
+				// Discard data that was sent before the error
+				cmd.stmt.rebind = true
+				my.sendCmd(_COM_STMT_RESET, cmd.stmt.id)
+				cmd.reset = true
+			} else {
+				cmd.stmt.writeCmdExec()
+			}
+		}
+		cmd.sent = true
+		cmd.seq = my.seq
+	}
+	// Network errors are reported here (bufio.Writer keeps the first one)
+	if err = my.wr.Flush(); err != nil {
+		return
+	}
+	my.unreaded_reply = true
+	return
+}
+
+// Reads responses of all sent commands.
+func (p *Pipeline) receive(cmds []pipeCmd, results []mysql.PipelineResult) error {
+	my := p.my
+	// Retrieving warnings requires sending a command
+	auto_warn := my.auto_warn
+	my.auto_warn = false
+	defer func() {
+		my.auto_warn = auto_warn
+	}()
+	for ii := range cmds {
+		cmd, r := &cmds[ii], &results[ii]
+		if !cmd.sent {
+			r.Err = cmd.err
+			continue
+		}
+		// Response of this command follows the command packets
+		my.seq = cmd.seq
+		if cmd.reset {
+			if _, err := my.response(); !isCmdError(err) {
+				return err
+			}
+			r.Err = cmd.err
+			continue
+		}
+		res, err := my.response()
+		if err != nil {
+			if !isCmdError(err) {
+				return err
+			}
+			r.Err = err
+			continue
+		}
+		if cmd.stmt != nil {
+			res.binary = true
+			cmd.stmt.updateFields(res)
+		}
+		r.Res = res
+		r.Rows, err = res.GetRows()
+		for err == nil && res.MoreResults() {
+			// Only the first result is returned, read the others
+			if res, err = res.nextResult(); err == nil {
+				_, err = res.GetRows()
+			}
+		}
+		if err != nil {
+			if !isCmdError(err) {
+				return err
+			}
+			// Error packet ends the reply
+			r.Err = err
+		}
+	}
+	my.unreaded_reply = false
+	return nil
+}
+
+// Reads the response of the command.
+func (my *Conn) response() (res *Result, err error) {
+	defer catchError(&err)
+
+	res = my.getResponse()
+	return
+}
+
+// Returns true if err is nil or is the error reported by the server for the
+// command (the connection can be used after it).
+func isCmdError(err error) bool {
+	if err == nil {
+		return true
+	}
+	_, ok := err.(*mysql.Error)
+	return ok
+}
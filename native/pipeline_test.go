@@ -0,0 +1,182 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/ziutek/mymysql/mysql"
+	"net"
+	"testing"
+)
+
+// Response packets start with sequence number 1
+func response(payloads ...[]byte) []byte {
+	return pktStreamSeq(1, payloads...)
+}
+
+func okPkt(affected byte) []byte {
+	return []byte{0, affected, 0, 2, 0, 0, 0}
+}
+
+func fieldPkt(name string, typ byte) []byte {
+	var buf bytes.Buffer
+	for _, s := range []string{"def", "test", "T", "T", name, name} {
+		writeStr(&buf, s)
+	}
+	buf.Write([]byte{0x0c, 33, 0})
+	writeU32(&buf, 10)
+	buf.Write([]byte{typ, 0, 0, 0, 0, 0})
+	return buf.Bytes()
+}
+
+func TestPipeline(t *testing.T) {
+	nc, peer := net.Pipe()
+	defer nc.Close()
+	defer peer.Close()
+
+	var out bytes.Buffer
+	var in []byte
+	// max_allowed_packet is read before the pipeline
+	in = append(in, response(
+		[]byte{1}, fieldPkt("@@max_allowed_packet", MYSQL_TYPE_LONGLONG),
+		eofPkt, []byte("\x071048576"), eofPkt,
+	)...)
+	in = append(in, response(okPkt(1))...)
+	in = append(in, response(
+		[]byte("\xff\x28\x04#42000You have an error in your SQL syntax"),
+	)...)
+	in = append(in, response(
+		[]byte{1}, fieldPkt("s", MYSQL_TYPE_VAR_STRING), eofPkt,
+		[]byte("\x01a"), []byte("\x01b"), eofPkt,
+	)...)
+	in = append(in, response(okPkt(2))...)
+	my := &Conn{
+		net_conn: nc,
+		rd:       bufio.NewReader(bytes.NewReader(in)),
+		wr:       bufio.NewWriter(&out),
+	}
+	stmt := &Stmt{my: my, id: 3, param_count: 1, params: make([]*paramValue, 1)}
+	other := &Stmt{my: &Conn{}}
+
+	p := my.Pipeline()
+	p.Query("insert T values (%d)", 1)
+	p.Query("bad")
+	p.Exec(stmt) // Parameters not binded
+	p.Exec(other)
+	p.Query("select s from T")
+	p.Exec(stmt, 2)
+	if p.Len() != 6 {
+		t.Fatal("Bad pipeline length:", p.Len())
+	}
+	results, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Len() != 0 || my.unreaded_reply {
+		t.Fatal("Pipeline not finished")
+	}
+
+	// Every command starts with sequence number 0
+	var cmds []byte
+	for data := out.Bytes(); len(data) != 0; {
+		n := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		if data[3] != 0 {
+			t.Fatalf("Bad sequence number of command %d: %d", len(cmds), data[3])
+		}
+		cmds = append(cmds, data[4])
+		data = data[4+n:]
+	}
+	exp_cmds := []byte{
+		_COM_QUERY, _COM_QUERY, _COM_QUERY, _COM_QUERY, _COM_STMT_EXECUTE,
+	}
+	if !bytes.Equal(cmds, exp_cmds) {
+		t.Fatalf("Bad commands: %v", cmds)
+	}
+	if my.max_allowed != 1048576 {
+		t.Fatal("Bad max_allowed:", my.max_allowed)
+	}
+
+	if len(results) != 6 {
+		t.Fatal("Bad number of results:", len(results))
+	}
+	if r := results[0]; r.Err != nil || r.Res.AffectedRows() != 1 {
+		t.Fatalf("0: %+v", r)
+	}
+	if e, ok := results[1].Err.(*mysql.Error); !ok || e.Code != 1064 {
+		t.Fatal("1: expected syntax error, got:", results[1].Err)
+	}
+	if results[2].Err != BIND_COUNT_ERROR {
+		t.Fatal("2: expected BIND_COUNT_ERROR, got:", results[2].Err)
+	}
+	if results[3].Err != PIPELINE_STMT_ERROR {
+		t.Fatal("3: expected PIPELINE_STMT_ERROR, got:", results[3].Err)
+	}
+	r := results[4]
+	if r.Err != nil || len(r.Rows) != 2 || r.Rows[0].Str(0) != "a" ||
+		r.Rows[1].Str(0) != "b" {
+		t.Fatalf("4: %+v", r)
+	}
+	if r := results[5]; r.Err != nil || r.Res.AffectedRows() != 2 {
+		t.Fatalf("5: %+v", r)
+	}
+}
+
+func TestPipelineReprepare(t *testing.T) {
+	nc, peer := net.Pipe()
+	defer nc.Close()
+	defer peer.Close()
+
+	var out bytes.Buffer
+	var in []byte
+	in = append(in, response(
+		[]byte("\xff\x4f\x06#HY000Prepared statement needs to be re-prepared"),
+	)...)
+	in = append(in, response(okPkt(1))...)
+	in = append(in, response(
+		[]byte{0, 4, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
+		fieldPkt("?", MYSQL_TYPE_VAR_STRING), eofPkt,
+	)...)
+	my := &Conn{
+		net_conn:    nc,
+		rd:          bufio.NewReader(bytes.NewReader(in)),
+		wr:          bufio.NewWriter(&out),
+		stmt_map:    make(map[uint32]*Stmt),
+		max_allowed: 1 << 20,
+	}
+	stmt := &Stmt{
+		my: my, id: 3, sql: "insert T values (?)",
+		param_count: 1, params: make([]*paramValue, 1),
+	}
+	my.stmt_map[stmt.id] = stmt
+
+	p := my.Pipeline()
+	p.Exec(stmt, 1)
+	p.Query("insert T values (2)")
+	results, err := p.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Statement is prepared again but isn't executed
+	var cmds []byte
+	for data := out.Bytes(); len(data) != 0; {
+		n := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		cmds = append(cmds, data[4])
+		data = data[4+n:]
+	}
+	exp_cmds := []byte{
+		_COM_STMT_EXECUTE, _COM_QUERY, _COM_STMT_PREPARE, _COM_STMT_CLOSE,
+	}
+	if !bytes.Equal(cmds, exp_cmds) {
+		t.Fatalf("Bad commands: %v", cmds)
+	}
+	if stmt.id != 4 || my.stmt_map[4] != stmt {
+		t.Fatal("Statement not reprepared")
+	}
+	if e, ok := results[0].Err.(*mysql.Error); !ok ||
+		e.Code != mysql.ER_NEED_REPREPARE {
+		t.Fatal("0: expected ER_NEED_REPREPARE, got:", results[0].Err)
+	}
+	if r := results[1]; r.Err != nil || r.Res.AffectedRows() != 1 {
+		t.Fatalf("1: %+v", r)
+	}
+}
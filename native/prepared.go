@@ -2,6 +2,7 @@ package native
 
 import (
 	"github.com/ziutek/mymysql/mysql"
+	"io"
 	"log"
 )
 
@@ -15,6 +16,11 @@ type Stmt struct {
 	rebind bool
 	binded bool
 
+	evicted bool // Closed on server side by the statement cache
+
+	names    []string         // Names of named parameters (nil if positional)
+	name_map map[string][]int // Maps parameter name to parameter numbers
+
 	fields []*mysql.Field
 	fc_map map[string]int // Maps field name to column number
 
@@ -45,6 +51,20 @@ func (stmt *Stmt) WarnCount() int {
 }
 
 func (stmt *Stmt) sendCmdExec() {
+	stmt.convParams()
+	stmt.writeCmdExec()
+}
+
+// Converts values that are known only at execution time
+func (stmt *Stmt) convParams() {
+	for _, param := range stmt.params {
+		if param.conv != nil && convValue(param) {
+			stmt.rebind = true
+		}
+	}
+}
+
+func (stmt *Stmt) writeCmdExec() {
 	// Calculate packet length and NULL bitmap
 	null_bitmap := make([]byte, (stmt.param_count+7)>>3)
 	pkt_len := 1 + 4 + 1 + 4 + 1 + len(null_bitmap)
@@ -92,10 +112,170 @@ func (stmt *Stmt) sendCmdExec() {
 	stmt.rebind = false
 }
 
+// Size of chunks of io.Reader parameters
+const longDataChunk = 64 * 1024
+
+// Reads server max_allowed_packet variable if it is unknown.
+func (my *Conn) readMaxAllowed() error {
+	if my.max_allowed != 0 {
+		return nil
+	}
+	row, _, err := my.QueryFirst("SELECT @@max_allowed_packet")
+	if err != nil {
+		return err
+	}
+	my.max_allowed, err = row.IntErr(0)
+	return err
+}
+
+// Returns the buffer for reading io.Reader parameters. Its size is
+// longDataChunk but chunk with command header can't be longer than server
+// max_allowed_packet. Variable is read from the server when the buffer is
+// used for the first time (Pipeline.Run reads it before the commands are
+// written).
+func (my *Conn) longDataBuf() []byte {
+	if err := my.readMaxAllowed(); err != nil {
+		panic(err)
+	}
+	size := longDataChunk
+	// Command header: 1 + 4 + 2 bytes
+	if my.max_allowed-7 < size {
+		size = my.max_allowed - 7
+		if size <= 0 {
+			panic(SMALL_PKT_SIZE_ERROR)
+		}
+	}
+	if cap(my.long_data) < size {
+		my.long_data = make([]byte, size)
+	}
+	return my.long_data[:size]
+}
+
+// Returns current offsets of binded io.Readers (by parameter number). ok is
+// false if some reader isn't io.Seeker, so its data can't be sent again.
+func (stmt *Stmt) readerOffsets() (offs map[int]int64, ok bool) {
+	for ii, param := range stmt.params {
+		if param.reader == nil {
+			continue
+		}
+		rd := param.reader()
+		if rd == nil {
+			continue
+		}
+		sk, is_seeker := rd.(io.Seeker)
+		if !is_seeker {
+			return nil, false
+		}
+		off, err := sk.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, false
+		}
+		if offs == nil {
+			offs = make(map[int]int64)
+		}
+		offs[ii] = off
+	}
+	return offs, true
+}
+
+// Seeks binded io.Readers back to offsets returned by readerOffsets.
+func (stmt *Stmt) rewindReaders(offs map[int]int64) {
+	for ii, off := range offs {
+		sk := stmt.params[ii].reader().(io.Seeker)
+		if _, err := sk.Seek(off, io.SeekStart); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Sends data from binded io.Readers using COM_STMT_SEND_LONG_DATA.
+func (stmt *Stmt) sendReaders() (err error) {
+	defer catchError(&err)
+
+	var buf []byte
+	for ii, param := range stmt.params {
+		if param.reader == nil {
+			continue
+		}
+		rd := param.reader()
+		if rd == nil {
+			// NULL
+			continue
+		}
+		if buf == nil {
+			buf = stmt.my.longDataBuf()
+		}
+		// Empty data is sent too (otherwise the value would be NULL)
+		for sent := false; ; sent = true {
+			nn, err := io.ReadFull(rd, buf)
+			if nn != 0 || !sent {
+				stmt.my.sendCmd(
+					_COM_STMT_SEND_LONG_DATA,
+					stmt.id, uint16(ii), buf[:nn],
+				)
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return
+}
+
+// Sends EXEC command with binded parameters and reads the response
+func (stmt *Stmt) exec() (res *Result, err error) {
+	defer catchError(&err)
+
+	stmt.sendCmdExec()
+	res = stmt.my.getResponse()
+	return
+}
+
+// Prepares the statement again, after the server reported that it must be
+// reprepared (eg. because of table structure change).
+func (stmt *Stmt) reprepare() {
+	my := stmt.my
+	new_stmt, err := my.prepare(stmt.sql)
+	if err != nil {
+		panic(err)
+	}
+	// Deallocate the old statement on the server side
+	my.sendCmd(_COM_STMT_CLOSE, stmt.id)
+	delete(my.stmt_map, stmt.id)
+	my.stmt_map[new_stmt.id] = stmt
+
+	stmt.id = new_stmt.id
+	stmt.fields = new_stmt.fields
+	stmt.fc_map = new_stmt.fc_map
+	stmt.field_count = new_stmt.field_count
+	stmt.rebind = true
+}
+
+// Updates fields of stmt if the server returned different set of columns
+// (table structure was changed and statement was reprepared by the server).
+func (stmt *Stmt) updateFields(res *Result) {
+	if res.StatusOnly() {
+		return
+	}
+	changed := len(res.fields) != len(stmt.fields)
+	for ii := 0; !changed && ii < len(res.fields); ii++ {
+		f, sf := res.fields[ii], stmt.fields[ii]
+		changed = f.Name != sf.Name || f.Type != sf.Type || f.Flags != sf.Flags
+	}
+	if changed {
+		stmt.fields = res.fields
+		stmt.fc_map = res.fc_map
+		stmt.field_count = res.field_count
+	}
+}
+
 func (my *Conn) getPrepareResult(stmt *Stmt) interface{} {
 loop:
 	pr := my.newPktReader() // New reader for next packet
-	pkt0 := readByte(pr)
+	pkt0 := pr.readByte()
 
 	//log.Println("pkt0:", pkt0, "stmt:", stmt)
 
@@ -1,10 +1,13 @@
 package native
 
 import (
+	"fmt"
 	"github.com/ziutek/mymysql/mysql"
+	"io"
 	"log"
 	"math"
 	"strconv"
+	"time"
 )
 
 type Result struct {
@@ -26,11 +29,20 @@ type Result struct {
 	// You can use the SHOW WARNINGS query for details.
 	warning_count int
 
+	// Warnings fetched after the reply was read (see Conn.SetAutoWarnings)
+	warnings []mysql.Warning
+
 	// MySQL server status immediately after the query execution
 	status uint16
 
 	// Seted by GetRow if it returns nil row
 	eor_returned bool
+
+	// Last row stream returned by ScanStream
+	stream *rowStream
+
+	// Decoders used by ScanInto
+	into *intoPlan
 }
 
 // Returns true if this is status result that includes no result set
@@ -55,6 +67,11 @@ func (res *Result) Message() string {
 	return string(res.message)
 }
 
+// Returns parsed Message (eg. number of matched and changed rows for UPDATE).
+func (res *Result) Info() (mysql.Info, error) {
+	return mysql.ParseInfo(string(res.message))
+}
+
 func (res *Result) AffectedRows() uint64 {
 	return res.affected_rows
 }
@@ -67,6 +84,48 @@ func (res *Result) WarnCount() int {
 	return res.warning_count
 }
 
+// Returns warnings generated by the command. They are available only if
+// automatic warnings retrieval is enabled (see Conn.SetAutoWarnings) and after
+// the whole reply was read. For multi result queries only warnings of the
+// last statement are available.
+func (res *Result) Warnings() []mysql.Warning {
+	return res.warnings
+}
+
+// Server status flags. For results that contain a result set they are valid
+// after all rows were read.
+
+// Returns true if a transaction is active.
+func (res *Result) InTransaction() bool {
+	return res.status&_SERVER_STATUS_IN_TRANS != 0
+}
+
+// Returns true if autocommit mode is enabled.
+func (res *Result) Autocommit() bool {
+	return res.status&_SERVER_STATUS_AUTOCOMMIT != 0
+}
+
+// Returns true if the query didn't use any index (full table scan).
+func (res *Result) NoIndexUsed() bool {
+	return res.status&_SERVER_QUERY_NO_INDEX_USED != 0
+}
+
+// Returns true if the server couldn't find a good index for the query.
+func (res *Result) NoGoodIndexUsed() bool {
+	return res.status&_SERVER_QUERY_NO_GOOD_INDEX_USED != 0
+}
+
+// Returns true if the server opened a cursor for the query.
+func (res *Result) CursorExists() bool {
+	return res.status&_SERVER_STATUS_CURSOR_EXISTS != 0
+}
+
+// Returns true if the result set contains values of OUT and INOUT parameters
+// of the procedure called by prepared statement (see mysql.ExecCall).
+func (res *Result) IsOutParams() bool {
+	return res.status&_SERVER_PS_OUT_PARAMS != 0
+}
+
 func (res *Result) MakeRow() mysql.Row {
 	return make(mysql.Row, res.field_count)
 }
@@ -74,7 +133,7 @@ func (res *Result) MakeRow() mysql.Row {
 func (my *Conn) getResult(res *Result, row mysql.Row) *Result {
 loop:
 	pr := my.newPktReader() // New reader for next packet
-	pkt0 := readByte(pr)
+	pkt0 := pr.readByte()
 
 	if pkt0 == 255 {
 		// Error packet
@@ -98,8 +157,8 @@ loop:
 		}
 	} else {
 		switch {
-		case pkt0 == 254:
-			// EOF packet
+		case pkt0 == 254 && pr.remain < 8:
+			// EOF packet (row can start with 254 only if it is long)
 			res.warning_count, res.status = my.getEofPacket(pr)
 			my.status = res.status
 			return res
@@ -114,7 +173,7 @@ loop:
 			// Read next packet
 			goto loop
 
-		case pkt0 < 254 && res.field_count == len(res.fields):
+		case pkt0 <= 254 && res.field_count == len(res.fields):
 			// Row Data Packet
 			if len(row) != res.field_count {
 				panic(ROW_LENGTH_ERROR)
@@ -161,15 +220,18 @@ func (my *Conn) getErrorPacket(pr *pktReader) {
 	}
 	var err mysql.Error
 	err.Code = readU16(pr)
-	if readByte(pr) != '#' {
-		panic(PKT_ERROR)
+	if readByte(pr) == '#' {
+		err.SqlState = string(read(pr, 5))
+	} else {
+		// Error sent before protocol negotiation has no SQLSTATE
+		pr.unreadByte()
 	}
-	read(pr, 5)
 	err.Msg = pr.readAll()
 	pr.checkEof()
 
 	if my.Debug {
-		log.Printf(tab8s+"code=0x%x msg=\"%s\"", err.Code, err.Msg)
+		log.Printf(tab8s+"code=0x%x state=%s msg=\"%s\"",
+			err.Code, err.SqlState, err.Msg)
 	}
 	panic(&err)
 }
@@ -178,8 +240,8 @@ func (my *Conn) getEofPacket(pr *pktReader) (warn_count int, status uint16) {
 	if my.Debug {
 		log.Printf("[%2d ->] EOF packet:", my.seq-1)
 	}
-	warn_count = int(readU16(pr))
-	status = readU16(pr)
+	warn_count = int(pr.readU16())
+	status = pr.readU16()
 	pr.checkEof()
 
 	if my.Debug {
@@ -237,21 +299,38 @@ func (my *Conn) getFieldPacket(pr *pktReader) (field *mysql.Field) {
 	return
 }
 
+// Reads the rest of row packet. If shared is true the packet is read into
+// my.row_buf (that is reused for every row) otherwise into a new buffer.
+func (my *Conn) readRow(pr *pktReader, shared bool) []byte {
+	if !shared {
+		return pr.appendAll(nil)
+	}
+	my.row_buf = pr.appendAll(my.row_buf[:0])
+	return my.row_buf
+}
+
 func (my *Conn) getTextRowPacket(pr *pktReader, res *Result, row mysql.Row) {
 	if my.Debug {
 		log.Printf("[%2d ->] Text row data packet", my.seq-1)
 	}
 	pr.unreadByte()
+	buf := my.readRow(pr, my.shared_rows)
 
 	for ii := 0; ii < res.field_count; ii++ {
-		bin, null := readNullBin(pr)
-		if null {
+		bin, null, n := decodeNullBin(buf)
+		switch {
+		case null:
 			row[ii] = nil
-		} else {
+		case my.typed_text && !isBytesType(res.fields[ii].Type):
+			row[ii] = textValue(bin, res.fields[ii])
+		default:
 			row[ii] = bin
 		}
+		buf = buf[n:]
+	}
+	if len(buf) != 0 {
+		panic(PKT_LONG_ERROR)
 	}
-	pr.checkEof()
 }
 
 func (my *Conn) getBinRowPacket(pr *pktReader, res *Result, row mysql.Row) {
@@ -259,69 +338,248 @@ func (my *Conn) getBinRowPacket(pr *pktReader, res *Result, row mysql.Row) {
 		log.Printf("[%2d ->] Binary row data packet", my.seq-1)
 	}
 	// First byte was readed by getResult
-
-	null_bitmap := make([]byte, (res.field_count+7+2)>>3)
-	readFull(pr, null_bitmap)
+	null_bitmap, buf := splitBinRow(
+		my.readRow(pr, my.shared_rows), res.field_count,
+	)
 
 	for ii, field := range res.fields {
-		null_byte := (ii + 2) >> 3
-		null_mask := byte(1) << uint(2+ii-(null_byte<<3))
-		if null_bitmap[null_byte]&null_mask != 0 {
+		if isBinNull(null_bitmap, ii) {
 			// Null field
 			row[ii] = nil
 			continue
 		}
-		typ := field.Type
-		unsigned := (field.Flags & _FLAG_UNSIGNED) != 0
-		switch typ {
-		case MYSQL_TYPE_TINY:
-			if unsigned {
-				row[ii] = readByte(pr)
-			} else {
-				row[ii] = int8(readByte(pr))
-			}
-		case MYSQL_TYPE_SHORT, MYSQL_TYPE_YEAR:
-			if unsigned {
-				row[ii] = readU16(pr)
-			} else {
-				row[ii] = int16(readU16(pr))
-			}
-		case MYSQL_TYPE_LONG, MYSQL_TYPE_INT24:
-			if unsigned {
-				row[ii] = readU32(pr)
-			} else {
-				row[ii] = int32(readU32(pr))
-			}
-		case MYSQL_TYPE_LONGLONG:
-			if unsigned {
-				row[ii] = readU64(pr)
-			} else {
-				row[ii] = int64(readU64(pr))
+		val, n := binValueBytes(buf, field)
+		buf = buf[n:]
+		if isBytesType(field.Type) {
+			row[ii] = val
+		} else {
+			row[ii] = binValue(val, field)
+		}
+	}
+}
+
+// Splits binary row payload (without its first byte) into the NULL bitmap
+// and column values.
+func splitBinRow(buf []byte, field_count int) (null_bitmap, vals []byte) {
+	nb := (field_count + 7 + 2) >> 3
+	if len(buf) < nb {
+		panic(PKT_ERROR)
+	}
+	return buf[:nb], buf[nb:]
+}
+
+// Returns true if the bitmap of binary row marks column ii as NULL.
+func isBinNull(null_bitmap []byte, ii int) bool {
+	null_byte := (ii + 2) >> 3
+	null_mask := byte(1) << uint(2+ii-(null_byte<<3))
+	return null_bitmap[null_byte]&null_mask != 0
+}
+
+// Returns true if the value of type typ is length encoded in binary row
+func isLenEncType(typ byte) bool {
+	switch typ {
+	case MYSQL_TYPE_DECIMAL, MYSQL_TYPE_NEWDECIMAL, MYSQL_TYPE_STRING,
+		MYSQL_TYPE_VAR_STRING, MYSQL_TYPE_VARCHAR, MYSQL_TYPE_BIT,
+		MYSQL_TYPE_BLOB, MYSQL_TYPE_TINY_BLOB, MYSQL_TYPE_MEDIUM_BLOB,
+		MYSQL_TYPE_LONG_BLOB, MYSQL_TYPE_SET, MYSQL_TYPE_ENUM,
+		MYSQL_TYPE_GEOMETRY:
+		return true
+	}
+	return false
+}
+
+func isDecimalType(typ byte) bool {
+	return typ == MYSQL_TYPE_DECIMAL || typ == MYSQL_TYPE_NEWDECIMAL
+}
+
+// Returns true if the value of type typ is stored in Row as []byte
+func isBytesType(typ byte) bool {
+	return isLenEncType(typ) && !isDecimalType(typ)
+}
+
+func parseDecimal(dec []byte) float64 {
+	val, err := strconv.ParseFloat(string(dec), 64)
+	if err != nil {
+		panic("MySQL server returned wrong decimal value: " + string(dec))
+	}
+	return val
+}
+
+// Returns the size of binary value of type typ if it has fixed size or 0.
+func fixedBinSize(typ byte) int {
+	switch typ {
+	case MYSQL_TYPE_TINY:
+		return 1
+	case MYSQL_TYPE_SHORT, MYSQL_TYPE_YEAR:
+		return 2
+	case MYSQL_TYPE_LONG, MYSQL_TYPE_INT24, MYSQL_TYPE_FLOAT:
+		return 4
+	case MYSQL_TYPE_LONGLONG, MYSQL_TYPE_DOUBLE:
+		return 8
+	}
+	return 0
+}
+
+// Returns not NULL value of field that starts at buf[0] (binary row) and the
+// number of bytes it takes in buf. Returned value doesn't contain its length
+// (length encoded values, date/time values).
+func binValueBytes(buf []byte, field *mysql.Field) ([]byte, int) {
+	if isLenEncType(field.Type) {
+		bin, null, n := decodeNullBin(buf)
+		if null {
+			panic(UNEXP_NULL_LCS_ERROR)
+		}
+		return bin, n
+	}
+	if n := fixedBinSize(field.Type); n != 0 {
+		if len(buf) < n {
+			panic(PKT_ERROR)
+		}
+		return buf[:n], n
+	}
+	// Date/time value: the first byte is the length of the rest
+	if len(buf) == 0 {
+		panic(PKT_ERROR)
+	}
+	var n int
+	switch field.Type {
+	case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE, MYSQL_TYPE_DATETIME,
+		MYSQL_TYPE_TIMESTAMP:
+		n = 1 + timeLen(buf[0])
+	case MYSQL_TYPE_TIME:
+		n = 1 + durationLen(buf[0])
+	default:
+		panic(UNK_MYSQL_TYPE_ERROR)
+	}
+	if len(buf) < n {
+		panic(PKT_ERROR)
+	}
+	return buf[1:n], n
+}
+
+// Converts not NULL value returned by binValueBytes to the value stored in
+// Row. Byte slice values aren't copied.
+func binValue(val []byte, field *mysql.Field) interface{} {
+	unsigned := (field.Flags & _FLAG_UNSIGNED) != 0
+	switch field.Type {
+	case MYSQL_TYPE_TINY:
+		if unsigned {
+			return val[0]
+		}
+		return int8(val[0])
+	case MYSQL_TYPE_SHORT, MYSQL_TYPE_YEAR:
+		if unsigned {
+			return DecodeU16(val)
+		}
+		return int16(DecodeU16(val))
+	case MYSQL_TYPE_LONG, MYSQL_TYPE_INT24:
+		if unsigned {
+			return DecodeU32(val)
+		}
+		return int32(DecodeU32(val))
+	case MYSQL_TYPE_LONGLONG:
+		if unsigned {
+			return DecodeU64(val)
+		}
+		return int64(DecodeU64(val))
+	case MYSQL_TYPE_FLOAT:
+		return math.Float32frombits(DecodeU32(val))
+	case MYSQL_TYPE_DOUBLE:
+		return math.Float64frombits(DecodeU64(val))
+	case MYSQL_TYPE_DECIMAL, MYSQL_TYPE_NEWDECIMAL:
+		return parseDecimal(val)
+	case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE:
+		return dateOf(decodeTime(val))
+	case MYSQL_TYPE_DATETIME, MYSQL_TYPE_TIMESTAMP:
+		return decodeTime(val)
+	case MYSQL_TYPE_TIME:
+		return decodeDuration(val)
+	}
+	if !isLenEncType(field.Type) {
+		panic(UNK_MYSQL_TYPE_ERROR)
+	}
+	return val
+}
+
+// Converts not NULL value of field from text row to the value of the same
+// type as binValue returns (see Conn.SetTypedText).
+func textValue(val []byte, field *mysql.Field) interface{} {
+	var (
+		v   interface{}
+		err error
+	)
+	str := string(val)
+	switch field.Type {
+	case MYSQL_TYPE_TINY, MYSQL_TYPE_SHORT, MYSQL_TYPE_YEAR, MYSQL_TYPE_INT24,
+		MYSQL_TYPE_LONG, MYSQL_TYPE_LONGLONG:
+		bits := 8 * fixedBinSize(field.Type)
+		if field.Flags&_FLAG_UNSIGNED != 0 {
+			var u uint64
+			u, err = strconv.ParseUint(str, 10, bits)
+			switch bits {
+			case 8:
+				v = uint8(u)
+			case 16:
+				v = uint16(u)
+			case 32:
+				v = uint32(u)
+			default:
+				v = u
 			}
-		case MYSQL_TYPE_FLOAT:
-			row[ii] = math.Float32frombits(readU32(pr))
-		case MYSQL_TYPE_DOUBLE:
-			row[ii] = math.Float64frombits(readU64(pr))
-		case MYSQL_TYPE_DECIMAL, MYSQL_TYPE_NEWDECIMAL:
-			dec := string(readBin(pr))
-			var err error
-			row[ii], err = strconv.ParseFloat(dec, 64)
-			if err != nil {
-				panic("MySQL server returned wrong decimal value: " + dec)
+		} else {
+			var i int64
+			i, err = strconv.ParseInt(str, 10, bits)
+			switch bits {
+			case 8:
+				v = int8(i)
+			case 16:
+				v = int16(i)
+			case 32:
+				v = int32(i)
+			default:
+				v = i
 			}
-		case MYSQL_TYPE_STRING, MYSQL_TYPE_VAR_STRING, MYSQL_TYPE_VARCHAR,
-			MYSQL_TYPE_BIT, MYSQL_TYPE_BLOB, MYSQL_TYPE_TINY_BLOB,
-			MYSQL_TYPE_MEDIUM_BLOB, MYSQL_TYPE_LONG_BLOB, MYSQL_TYPE_SET,
-			MYSQL_TYPE_ENUM, MYSQL_TYPE_GEOMETRY:
-			row[ii] = readBin(pr)
-		case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE:
-			row[ii] = readDate(pr)
-		case MYSQL_TYPE_DATETIME, MYSQL_TYPE_TIMESTAMP:
-			row[ii] = readTime(pr)
-		case MYSQL_TYPE_TIME:
-			row[ii] = readDuration(pr)
-		default:
-			panic(UNK_MYSQL_TYPE_ERROR)
 		}
+	case MYSQL_TYPE_FLOAT:
+		var f float64
+		f, err = strconv.ParseFloat(str, 32)
+		v = float32(f)
+	case MYSQL_TYPE_DOUBLE:
+		v, err = strconv.ParseFloat(str, 64)
+	case MYSQL_TYPE_DECIMAL, MYSQL_TYPE_NEWDECIMAL:
+		v = parseDecimal(val)
+	case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE:
+		v, err = mysql.ParseDate(str)
+	case MYSQL_TYPE_DATETIME, MYSQL_TYPE_TIMESTAMP:
+		v, err = mysql.ParseTime(str, time.Local)
+	case MYSQL_TYPE_TIME:
+		v, err = mysql.ParseDuration(str)
+	default:
+		return val
+	}
+	if err != nil {
+		panic(fmt.Errorf("MySQL server returned wrong value of column %s: %s",
+			field.Name, err))
+	}
+	return v
+}
+
+// Reads not NULL value of field from binary row
+func readBinValue(pr io.Reader, field *mysql.Field) interface{} {
+	switch field.Type {
+	case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE:
+		return readDate(pr)
+	case MYSQL_TYPE_DATETIME, MYSQL_TYPE_TIMESTAMP:
+		return readTime(pr)
+	case MYSQL_TYPE_TIME:
+		return readDuration(pr)
+	}
+	if isLenEncType(field.Type) {
+		return binValue(readBin(pr), field)
+	}
+	n := fixedBinSize(field.Type)
+	if n == 0 {
+		panic(UNK_MYSQL_TYPE_ERROR)
 	}
+	return binValue(read(pr, n), field)
 }
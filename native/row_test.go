@@ -0,0 +1,266 @@
+package native
+
+import (
+	"bytes"
+	"github.com/ziutek/mymysql/mysql"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var rowFields = []*mysql.Field{
+	{Name: "id", Type: MYSQL_TYPE_LONG},
+	{Name: "name", Type: MYSQL_TYPE_VAR_STRING},
+	{Name: "created", Type: MYSQL_TYPE_DATETIME},
+	{Name: "price", Type: MYSQL_TYPE_NEWDECIMAL},
+	{Name: "note", Type: MYSQL_TYPE_BLOB},
+}
+
+// Returns text row: id, "name-id", 2013-07-14 15:04:05, 12.5, NULL
+func textRow(id int) []byte {
+	var buf bytes.Buffer
+	writeStr(&buf, itoa(id))
+	writeStr(&buf, "name-"+itoa(id))
+	writeStr(&buf, "2013-07-14 15:04:05")
+	writeStr(&buf, "12.50")
+	writeByte(&buf, 251)
+	return buf.Bytes()
+}
+
+// Returns binary row with the same values as textRow
+func binRow(id int) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 1 << 6}) // header, NULL bitmap (note is NULL)
+	writeU32(&buf, uint32(id))
+	writeStr(&buf, "name-"+itoa(id))
+	writeTime(&buf, time.Date(2013, 7, 14, 15, 4, 5, 0, time.Local))
+	writeStr(&buf, "12.50")
+	return buf.Bytes()
+}
+
+func itoa(i int) string {
+	return string(mysql.Row{int64(i)}.Bin(0))
+}
+
+// Result that can read the same recorded packet stream many times
+type recResult struct {
+	*Result
+	data []byte
+	rd   *bytes.Reader
+}
+
+func newRecResult(binary bool, rows int) *recResult {
+	payloads := make([][]byte, rows+1)
+	for ii := 0; ii < rows; ii++ {
+		if binary {
+			payloads[ii] = binRow(ii + 1000)
+		} else {
+			payloads[ii] = textRow(ii + 1000)
+		}
+	}
+	payloads[rows] = eofPkt
+	r := &recResult{
+		Result: testResult(binary, rowFields),
+		data:   pktStream(payloads...),
+	}
+	r.rd = bytes.NewReader(r.data)
+	r.my.rd.Reset(r.rd)
+	return r
+}
+
+// Rewinds the stream
+func (r *recResult) reset() {
+	r.rd.Reset(r.data)
+	r.my.rd.Reset(r.rd)
+	r.my.seq = 0
+	r.my.unreaded_reply = true
+	r.eor_returned = false
+}
+
+func checkRow(t *testing.T, row mysql.Row, id int) {
+	created := time.Date(2013, 7, 14, 15, 4, 5, 0, time.Local)
+	if row.Int(0) != id || row.Str(1) != "name-"+itoa(id) ||
+		!row.Time(2, time.Local).Equal(created) || row.Float(3) != 12.5 ||
+		row[4] != nil {
+		t.Fatalf("Bad row %d: %v", id, row)
+	}
+}
+
+func testScanRow(t *testing.T, binary, shared bool) {
+	r := newRecResult(binary, 3)
+	r.my.SetSharedRowBuffer(shared)
+	row := r.MakeRow()
+	if err := r.ScanRow(row); err != nil {
+		t.Fatal(err)
+	}
+	checkRow(t, row, 1000)
+	clone := row.Clone()
+	name := row.Bin(1)
+	if err := r.ScanRow(row); err != nil {
+		t.Fatal(err)
+	}
+	checkRow(t, row, 1001)
+	checkRow(t, clone, 1000)
+	switch {
+	case shared && string(name) != "name-1001":
+		t.Fatalf("Row doesn't refer to the connection buffer: %q", name)
+	case !shared && string(name) != "name-1000":
+		t.Fatalf("Row refers to the connection buffer: %q", name)
+	}
+	// GetRow returns rows that can be retained
+	last, err := r.GetRow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.ScanRow(row); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+	checkRow(t, last, 1002)
+}
+
+func TestScanRowText(t *testing.T) {
+	testScanRow(t, false, false)
+	testScanRow(t, false, true)
+}
+
+func TestScanRowBinary(t *testing.T) {
+	testScanRow(t, true, false)
+	testScanRow(t, true, true)
+}
+
+func TestScanRowLong(t *testing.T) {
+	// Row that is split into many packets
+	big := bytes.Repeat([]byte("0123456789abcdef"), 0x110000)
+	var buf bytes.Buffer
+	writeBin(&buf, big)
+	writeStr(&buf, "1")
+	res := testResult(
+		false,
+		[]*mysql.Field{
+			{Name: "data", Type: MYSQL_TYPE_BLOB},
+			{Name: "id", Type: MYSQL_TYPE_LONG},
+		},
+		buf.Bytes(), eofPkt,
+	)
+	row := res.MakeRow()
+	if err := res.ScanRow(row); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(row.Bin(0), big) || row.Int(1) != 1 {
+		t.Fatal("Bad long row")
+	}
+	if err := res.ScanRow(row); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+}
+
+func TestScanRowTypedText(t *testing.T) {
+	fields := []*mysql.Field{
+		{Name: "a", Type: MYSQL_TYPE_TINY, Flags: _FLAG_UNSIGNED},
+		{Name: "b", Type: MYSQL_TYPE_SHORT},
+		{Name: "c", Type: MYSQL_TYPE_LONGLONG},
+		{Name: "d", Type: MYSQL_TYPE_FLOAT},
+		{Name: "e", Type: MYSQL_TYPE_DATE},
+		{Name: "f", Type: MYSQL_TYPE_TIME},
+		{Name: "g", Type: MYSQL_TYPE_BIT},
+		{Name: "h", Type: MYSQL_TYPE_TIME, Scale: 6},
+	}
+	var text, bin bytes.Buffer
+	for _, s := range []string{
+		"200", "-5", "-9000000000", "1.5", "2013-07-14", "-12:30:00", "\x01",
+		"12:30:00.123456",
+	} {
+		writeStr(&text, s)
+	}
+	bin.Write([]byte{0, 0, 0}) // header, NULL bitmap
+	writeByte(&bin, 200)
+	b, c := int16(-5), int64(-9000000000)
+	writeU16(&bin, uint16(b))
+	writeU64(&bin, uint64(c))
+	writeU32(&bin, 0x3fc00000) // 1.5
+	writeDate(&bin, mysql.Date{Year: 2013, Month: 7, Day: 14})
+	writeDuration(&bin, -(12*time.Hour + 30*time.Minute))
+	writeStr(&bin, "\x01")
+	writeDuration(&bin, 12*time.Hour+30*time.Minute+123456*time.Microsecond)
+
+	res := testResult(false, fields, text.Bytes(), eofPkt)
+	res.my.SetTypedText(true)
+	text_row, err := res.GetRow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin_row, err := testResult(true, fields, bin.Bytes(), eofPkt).GetRow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(text_row, bin_row) {
+		t.Fatalf("Text row %#v doesn't match binary row %#v", text_row, bin_row)
+	}
+}
+
+func scanRowAllocs(shared bool) float64 {
+	r := newRecResult(false, 10)
+	r.my.SetSharedRowBuffer(shared)
+	row := r.MakeRow()
+	return testing.AllocsPerRun(10, func() {
+		r.reset()
+		for r.ScanRow(row) == nil {
+		}
+	})
+}
+
+func TestScanRowAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("race detector allocates")
+	}
+	// With shared buffer only values stored in Row (interface values) may
+	// allocate: at most four in every row. Without shared buffer every row
+	// allocates its data too.
+	shared, own := scanRowAllocs(true), scanRowAllocs(false)
+	if shared > 40 {
+		t.Fatalf("Shared buffer: %v allocations per result", shared)
+	}
+	if own <= shared {
+		t.Fatalf("Own buffers: %v allocations per result, shared: %v", own, shared)
+	}
+}
+
+func benchmarkScanRow(b *testing.B, binary, shared bool) {
+	const rows = 100
+	r := newRecResult(binary, rows)
+	r.my.SetSharedRowBuffer(shared)
+	row := r.MakeRow()
+	b.SetBytes(int64(len(r.data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for ii := 0; ii < b.N; ii++ {
+		r.reset()
+		n := 0
+		for r.ScanRow(row) == nil {
+			n++
+		}
+		if n != rows {
+			b.Fatalf("Read %d rows", n)
+		}
+	}
+}
+
+// All benchmarks read a result of 100 rows per operation. With shared row
+// buffer only values stored in Row (interface values) allocate.
+
+func BenchmarkScanRowText(b *testing.B) {
+	benchmarkScanRow(b, false, false)
+}
+
+func BenchmarkScanRowBinary(b *testing.B) {
+	benchmarkScanRow(b, true, false)
+}
+
+func BenchmarkScanRowTextShared(b *testing.B) {
+	benchmarkScanRow(b, false, true)
+}
+
+func BenchmarkScanRowBinaryShared(b *testing.B) {
+	benchmarkScanRow(b, true, true)
+}
@@ -0,0 +1,502 @@
+package native
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/ziutek/mymysql/mysql"
+	"io"
+	"log"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Decoder of one column used by ScanInto. val is a not NULL value returned
+// by binValueBytes (binary rows) or the text of the value (text rows).
+type intoCol struct {
+	dec  func(dst interface{}, val []byte) error
+	null func(dst interface{}) error
+}
+
+type intoPlan struct {
+	types []reflect.Type // Types of destinations
+	cols  []intoCol
+}
+
+// Conversions of raw column values
+type colConv struct {
+	field  *mysql.Field
+	binary bool
+}
+
+func (cc colConv) unsigned() bool {
+	return cc.field.Flags&_FLAG_UNSIGNED != 0
+}
+
+func (cc colConv) isInt() bool {
+	switch cc.field.Type {
+	case MYSQL_TYPE_TINY, MYSQL_TYPE_SHORT, MYSQL_TYPE_YEAR, MYSQL_TYPE_INT24,
+		MYSQL_TYPE_LONG, MYSQL_TYPE_LONGLONG:
+		return true
+	}
+	return false
+}
+
+func (cc colConv) isNumber() bool {
+	switch cc.field.Type {
+	case MYSQL_TYPE_FLOAT, MYSQL_TYPE_DOUBLE, MYSQL_TYPE_DECIMAL,
+		MYSQL_TYPE_NEWDECIMAL:
+		return true
+	}
+	return cc.isInt()
+}
+
+func (cc colConv) isTime() bool {
+	switch cc.field.Type {
+	case MYSQL_TYPE_DATE, MYSQL_TYPE_NEWDATE, MYSQL_TYPE_DATETIME,
+		MYSQL_TYPE_TIMESTAMP:
+		return true
+	}
+	return false
+}
+
+// True if val is the text of the value
+func (cc colConv) isText() bool {
+	return !cc.binary || isLenEncType(cc.field.Type)
+}
+
+func (cc colConv) int64(val []byte) (int64, error) {
+	if !cc.binary {
+		return strconv.ParseInt(string(val), 10, 64)
+	}
+	u := DecodeU64(val)
+	if cc.unsigned() {
+		if int64(u) < 0 {
+			return 0, strconv.ErrRange
+		}
+		return int64(u), nil
+	}
+	// Sign extension
+	shift := uint(64 - 8*len(val))
+	return int64(u<<shift) >> shift, nil
+}
+
+func (cc colConv) uint64(val []byte) (uint64, error) {
+	if !cc.binary {
+		return strconv.ParseUint(string(val), 10, 64)
+	}
+	if cc.unsigned() {
+		return DecodeU64(val), nil
+	}
+	i, _ := cc.int64(val)
+	if i < 0 {
+		return 0, strconv.ErrRange
+	}
+	return uint64(i), nil
+}
+
+func (cc colConv) float64(val []byte) (float64, error) {
+	switch {
+	case cc.isText():
+		return strconv.ParseFloat(string(val), 64)
+	case cc.field.Type == MYSQL_TYPE_FLOAT:
+		return float64(math.Float32frombits(DecodeU32(val))), nil
+	case cc.field.Type == MYSQL_TYPE_DOUBLE:
+		return math.Float64frombits(DecodeU64(val)), nil
+	case cc.unsigned():
+		u, err := cc.uint64(val)
+		return float64(u), err
+	}
+	i, err := cc.int64(val)
+	return float64(i), err
+}
+
+func (cc colConv) time(val []byte) (time.Time, error) {
+	if cc.binary {
+		return decodeTime(val), nil
+	}
+	return mysql.ParseTime(string(val), time.Local)
+}
+
+// Appends the value formatted as in Row.Str to buf
+func (cc colConv) appendText(buf, val []byte) []byte {
+	switch {
+	case cc.isText():
+		return append(buf, val...)
+	case cc.isInt():
+		if cc.unsigned() {
+			return strconv.AppendUint(buf, DecodeU64(val), 10)
+		}
+		i, _ := cc.int64(val)
+		return strconv.AppendInt(buf, i, 10)
+	case cc.field.Type == MYSQL_TYPE_FLOAT:
+		return strconv.AppendFloat(
+			buf, float64(math.Float32frombits(DecodeU32(val))), 'g', -1, 32,
+		)
+	case cc.field.Type == MYSQL_TYPE_DOUBLE:
+		return strconv.AppendFloat(
+			buf, math.Float64frombits(DecodeU64(val)), 'g', -1, 64,
+		)
+	case cc.field.Type == MYSQL_TYPE_DATE || cc.field.Type == MYSQL_TYPE_NEWDATE:
+		return append(buf, dateOf(decodeTime(val)).String()...)
+	case cc.isTime():
+		return append(buf, mysql.TimeString(decodeTime(val))...)
+	}
+	// MYSQL_TYPE_TIME
+	return append(buf, mysql.DurationString(decodeDuration(val))...)
+}
+
+// Returns the value as it is stored in Row (byte slice isn't copied)
+func (cc colConv) value(val []byte) interface{} {
+	if cc.binary {
+		return binValue(val, cc.field)
+	}
+	return val
+}
+
+func intInto[T int | int8 | int16 | int32 | int64](cc colConv) func(interface{}, []byte) error {
+	return func(dst interface{}, val []byte) error {
+		i, err := cc.int64(val)
+		if err == nil && int64(T(i)) != i {
+			err = strconv.ErrRange
+		}
+		*dst.(*T) = T(i)
+		return err
+	}
+}
+
+func uintInto[T uint | uint8 | uint16 | uint32 | uint64](cc colConv) func(interface{}, []byte) error {
+	return func(dst interface{}, val []byte) error {
+		u, err := cc.uint64(val)
+		if err == nil && uint64(T(u)) != u {
+			err = strconv.ErrRange
+		}
+		*dst.(*T) = T(u)
+		return err
+	}
+}
+
+func floatInto[T float32 | float64](cc colConv) func(interface{}, []byte) error {
+	return func(dst interface{}, val []byte) error {
+		f, err := cc.float64(val)
+		*dst.(*T) = T(f)
+		return err
+	}
+}
+
+func zeroInto(dst interface{}) error {
+	reflect.ValueOf(dst).Elem().SetZero()
+	return nil
+}
+
+func skipInto(interface{}, []byte) error { return nil }
+
+func skipNullInto(interface{}) error { return nil }
+
+// Returns the decoder that stores values of any column using set
+func valueInto(cc colConv, set func(dst, v interface{}) error) intoCol {
+	return intoCol{
+		dec: func(dst interface{}, val []byte) error {
+			return set(dst, cc.value(val))
+		},
+		null: func(dst interface{}) error {
+			return set(dst, nil)
+		},
+	}
+}
+
+// Converts value from Row to the value accepted by sql.Scanner
+func scannerValue(v interface{}) interface{} {
+	if d, ok := v.(mysql.Date); ok {
+		if d.IsZero() {
+			return nil
+		}
+		return d.Localtime()
+	}
+	return v
+}
+
+// Returns the decoder of the column for destination dst or false if dst
+// can't be used for this column.
+func (cc colConv) intoCol(dst interface{}) (intoCol, bool) {
+	if dst == nil {
+		// Skip the column
+		return intoCol{skipInto, skipNullInto}, true
+	}
+	t := reflect.TypeOf(dst)
+	if t.Kind() != reflect.Ptr {
+		return intoCol{}, false
+	}
+	if dec := mysql.GetDecoder(t.Elem()); dec != nil {
+		return valueInto(cc, func(dst, v interface{}) error {
+			return dec(dst, v)
+		}), true
+	}
+	if _, ok := dst.(sql.Scanner); ok {
+		return valueInto(cc, func(dst, v interface{}) error {
+			return dst.(sql.Scanner).Scan(scannerValue(v))
+		}), true
+	}
+	if et := t.Elem(); et.Kind() == reflect.Ptr {
+		// NULL is stored as nil pointer
+		vt := et.Elem()
+		vc, ok := cc.intoCol(reflect.New(vt).Interface())
+		if !ok {
+			return vc, false
+		}
+		return intoCol{
+			dec: func(dst interface{}, val []byte) error {
+				p := reflect.New(vt)
+				err := vc.dec(p.Interface(), val)
+				reflect.ValueOf(dst).Elem().Set(p)
+				return err
+			},
+			null: zeroInto,
+		}, true
+	}
+	col := intoCol{null: zeroInto}
+	switch dst.(type) {
+	case *interface{}:
+		return valueInto(cc, func(dst, v interface{}) error {
+			if b, ok := v.([]byte); ok {
+				v = append([]byte(nil), b...)
+			}
+			*dst.(*interface{}) = v
+			return nil
+		}), true
+	case *string:
+		col.dec = func(dst interface{}, val []byte) error {
+			if cc.isText() {
+				*dst.(*string) = string(val)
+			} else {
+				*dst.(*string) = string(cc.appendText(nil, val))
+			}
+			return nil
+		}
+	case *[]byte:
+		col.dec = func(dst interface{}, val []byte) error {
+			// Reuse the backing array of the destination
+			d := dst.(*[]byte)
+			*d = cc.appendText((*d)[:0], val)
+			return nil
+		}
+	case *sql.RawBytes:
+		if !cc.isText() {
+			return col, false
+		}
+		col.dec = func(dst interface{}, val []byte) error {
+			*dst.(*sql.RawBytes) = val
+			return nil
+		}
+	case *time.Time:
+		if !cc.isTime() {
+			return col, false
+		}
+		col.dec = func(dst interface{}, val []byte) (err error) {
+			*dst.(*time.Time), err = cc.time(val)
+			return
+		}
+	case *mysql.Date:
+		if cc.field.Type != MYSQL_TYPE_DATE &&
+			cc.field.Type != MYSQL_TYPE_NEWDATE {
+			return col, false
+		}
+		col.dec = func(dst interface{}, val []byte) (err error) {
+			if cc.binary {
+				*dst.(*mysql.Date) = dateOf(decodeTime(val))
+				return nil
+			}
+			*dst.(*mysql.Date), err = mysql.ParseDate(string(val))
+			return
+		}
+	case *time.Duration:
+		if cc.field.Type != MYSQL_TYPE_TIME {
+			return col, false
+		}
+		col.dec = func(dst interface{}, val []byte) (err error) {
+			if cc.binary {
+				*dst.(*time.Duration) = decodeDuration(val)
+				return nil
+			}
+			*dst.(*time.Duration), err = mysql.ParseDuration(string(val))
+			return
+		}
+	case *bool:
+		if !cc.isInt() {
+			return col, false
+		}
+		col.dec = func(dst interface{}, val []byte) error {
+			i, err := cc.int64(val)
+			*dst.(*bool) = i != 0
+			return err
+		}
+	case *float32:
+		col.dec = floatInto[float32](cc)
+	case *float64:
+		col.dec = floatInto[float64](cc)
+	case *int:
+		col.dec = intInto[int](cc)
+	case *int8:
+		col.dec = intInto[int8](cc)
+	case *int16:
+		col.dec = intInto[int16](cc)
+	case *int32:
+		col.dec = intInto[int32](cc)
+	case *int64:
+		col.dec = intInto[int64](cc)
+	case *uint:
+		col.dec = uintInto[uint](cc)
+	case *uint8:
+		col.dec = uintInto[uint8](cc)
+	case *uint16:
+		col.dec = uintInto[uint16](cc)
+	case *uint32:
+		col.dec = uintInto[uint32](cc)
+	case *uint64:
+		col.dec = uintInto[uint64](cc)
+	default:
+		return col, false
+	}
+	switch dst.(type) {
+	case *float32, *float64:
+		return col, cc.isNumber()
+	case *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16,
+		*uint32, *uint64:
+		return col, cc.isInt()
+	}
+	return col, true
+}
+
+// Checks destinations and prepares decoders. The plan is reused as long as
+// the types of destinations don't change.
+func (res *Result) intoPlan(dest []interface{}) error {
+	if len(dest) != res.field_count {
+		return ROW_LENGTH_ERROR
+	}
+	if p := res.into; p != nil {
+		ii := 0
+		for ii < len(dest) && reflect.TypeOf(dest[ii]) == p.types[ii] {
+			ii++
+		}
+		if ii == len(dest) {
+			return nil
+		}
+	}
+	p := &intoPlan{
+		types: make([]reflect.Type, len(dest)),
+		cols:  make([]intoCol, len(dest)),
+	}
+	for ii, d := range dest {
+		field := res.fields[ii]
+		col, ok := colConv{field, res.binary}.intoCol(d)
+		if !ok {
+			return &ScanIntoError{ii, field.Name, fmt.Errorf(
+				"MySQL type 0x%x can't be stored in %T", field.Type, d,
+			)}
+		}
+		p.types[ii] = reflect.TypeOf(d)
+		p.cols[ii] = col
+	}
+	res.into = p
+	return nil
+}
+
+func (res *Result) getRowInto(dest []interface{}) (err error) {
+	defer catchError(&err)
+
+	my := res.my
+	pr := my.newPktReader()
+	pkt0 := pr.readByte()
+	switch {
+	case pkt0 == 255:
+		my.getErrorPacket(pr)
+	case pkt0 == 254 && pr.remain < 8:
+		// EOF packet (row can start with 254 only if it is long)
+		res.warning_count, res.status = my.getEofPacket(pr)
+		my.status = res.status
+		return io.EOF
+	}
+	var (
+		null_bitmap []byte
+		buf         []byte
+	)
+	if res.binary {
+		if my.Debug {
+			log.Printf("[%2d ->] Binary row data packet (into)", my.seq-1)
+		}
+		if pkt0 != 0 {
+			panic(UNK_RESULT_PKT_ERROR)
+		}
+		null_bitmap, buf = splitBinRow(my.readRow(pr, true), res.field_count)
+	} else {
+		if my.Debug {
+			log.Printf("[%2d ->] Text row data packet (into)", my.seq-1)
+		}
+		pr.unreadByte()
+		buf = my.readRow(pr, true)
+	}
+	// Whole row was read, so decoding errors don't break the reply
+	for ii, col := range res.into.cols {
+		var (
+			val  []byte
+			null bool
+			n    int
+		)
+		if res.binary {
+			if null = isBinNull(null_bitmap, ii); !null {
+				val, n = binValueBytes(buf, res.fields[ii])
+			}
+		} else {
+			val, null, n = decodeNullBin(buf)
+		}
+		buf = buf[n:]
+		var e error
+		if null {
+			e = col.null(dest[ii])
+		} else {
+			e = col.dec(dest[ii], val)
+		}
+		if e != nil && err == nil {
+			err = &ScanIntoError{ii, res.fields[ii].Name, e}
+		}
+	}
+	if !res.binary && len(buf) != 0 {
+		panic(PKT_LONG_ERROR)
+	}
+	return
+}
+
+// Like ScanRow but decodes column values directly into dest, without
+// boxing them in Row. Every destination must be a pointer to intX, uintX,
+// floatX, bool, string, []byte, sql.RawBytes, time.Time, time.Duration,
+// mysql.Date, interface{}, a type that implements sql.Scanner, a type with
+// registered decoder (see mysql.RegisterDecoder) or a pointer to any of them.
+// nil destination skips the column. NULL is stored as nil pointer, as zero
+// value (nil for interface{}, []byte, sql.RawBytes) or is passed to Scan
+// method or decoder.
+//
+// []byte destination reuses its backing array. sql.RawBytes refers to the
+// connection buffer, so it is valid only until next row is read.
+//
+// Destinations are checked when the first row is scanned and then only if
+// their types change. Returns *ScanIntoError if a column doesn't fit its
+// destination (the remaining columns are still decoded).
+func (res *Result) ScanInto(dest ...interface{}) error {
+	if err := res.checkScan(); err != nil {
+		return err
+	}
+	if res.StatusOnly() {
+		// There is no fields in result (OK result)
+		res.eor_returned = true
+		return io.EOF
+	}
+	if err := res.intoPlan(dest); err != nil {
+		return err
+	}
+	err := res.getRowInto(dest)
+	if err == io.EOF {
+		return res.endOfRows()
+	}
+	return err
+}
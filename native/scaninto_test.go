@@ -0,0 +1,170 @@
+package native
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"github.com/ziutek/mymysql/mysql"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func testScanInto(t *testing.T, binary bool) {
+	r := newRecResult(binary, 2)
+	var (
+		id      int64
+		name    []byte
+		created time.Time
+		price   float64
+		note    sql.NullString
+	)
+	name = make([]byte, 0, 64)
+	backing := &name[:1][0]
+	if err := r.ScanInto(&id, &name, &created, &price, &note); err != nil {
+		t.Fatal(err)
+	}
+	if id != 1000 || string(name) != "name-1000" || price != 12.5 ||
+		!created.Equal(time.Date(2013, 7, 14, 15, 4, 5, 0, time.Local)) ||
+		note.Valid {
+		t.Fatalf("Bad values: %d %q %v %v %v", id, name, created, price, note)
+	}
+	if &name[0] != backing {
+		t.Fatal("Backing array of []byte destination wasn't reused")
+	}
+
+	// Other types of destinations
+	var (
+		sid  string
+		raw  sql.RawBytes
+		iprc interface{}
+		nt   *string
+	)
+	nt = new(string)
+	err := r.ScanInto(&sid, &raw, nil, &iprc, &nt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nt != nil {
+		t.Fatal("NULL not stored as nil pointer")
+	}
+	prc := mysql.Row{iprc}.Str(0)
+	if sid != "1001" || string(raw) != "name-1001" ||
+		binary && prc != "12.5" || !binary && prc != "12.50" {
+		t.Fatalf("Bad values: %q %q %v", sid, raw, iprc)
+	}
+	if err = r.ScanInto(&id, &name, &created, &price, &note); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+}
+
+func TestScanIntoText(t *testing.T) {
+	testScanInto(t, false)
+}
+
+func TestScanIntoBinary(t *testing.T) {
+	testScanInto(t, true)
+}
+
+func TestScanIntoDuration(t *testing.T) {
+	fields := []*mysql.Field{{Name: "t", Type: MYSQL_TYPE_TIME, Scale: 6}}
+	exp := 12*time.Hour + 30*time.Minute + 123456*time.Microsecond
+	var text, bin bytes.Buffer
+	writeStr(&text, "12:30:00.123456")
+	bin.Write([]byte{0, 0}) // header, NULL bitmap
+	writeDuration(&bin, exp)
+	for _, res := range []*Result{
+		testResult(false, fields, text.Bytes(), eofPkt),
+		testResult(true, fields, bin.Bytes(), eofPkt),
+	} {
+		var d time.Duration
+		if err := res.ScanInto(&d); err != nil {
+			t.Fatal(err)
+		}
+		if d != exp {
+			t.Fatalf("Bad duration (binary=%t): %v", res.binary, d)
+		}
+	}
+}
+
+func TestScanIntoErrors(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		r := newRecResult(binary, 2)
+		var (
+			id    int8
+			name  string
+			price float64
+		)
+		// Wrong number of destinations
+		if err := r.ScanInto(&id); err != ROW_LENGTH_ERROR {
+			t.Fatal("Expected ROW_LENGTH_ERROR, got:", err)
+		}
+		// Wrong type of destination
+		var se *ScanIntoError
+		err := r.ScanInto(&id, &price, nil, nil, nil)
+		if !errors.As(err, &se) || se.Column != 1 {
+			t.Fatal("Expected ScanIntoError for column 1, got:", err)
+		}
+		// Overflow: remaining columns are decoded and the row is read
+		err = r.ScanInto(&id, &name, nil, &price, nil)
+		if !errors.As(err, &se) || se.Column != 0 ||
+			!errors.Is(err, strconv.ErrRange) {
+			t.Fatal("Expected ScanIntoError with ErrRange, got:", err)
+		}
+		if name != "name-1000" || price != 12.5 {
+			t.Fatalf("Bad values: %q %v", name, price)
+		}
+		var id64 int64
+		if err = r.ScanInto(&id64, &name, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if id64 != 1001 || name != "name-1001" {
+			t.Fatalf("Bad values: %d %q", id64, name)
+		}
+	}
+}
+
+func TestScanIntoAllocs(t *testing.T) {
+	r := newRecResult(true, 10)
+	var (
+		id      int64
+		name    = make([]byte, 0, 64)
+		created time.Time
+		price   float64
+		note    []byte
+	)
+	allocs := testing.AllocsPerRun(10, func() {
+		r.reset()
+		for r.ScanInto(&id, &name, &created, &price, &note) == nil {
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("%v allocations per result", allocs)
+	}
+}
+
+func BenchmarkScanInto(b *testing.B) {
+	const rows = 100
+	r := newRecResult(true, rows)
+	var (
+		id      int64
+		name    []byte
+		created time.Time
+		price   float64
+		note    []byte
+	)
+	b.SetBytes(int64(len(r.data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for ii := 0; ii < b.N; ii++ {
+		r.reset()
+		n := 0
+		for r.ScanInto(&id, &name, &created, &price, &note) == nil {
+			n++
+		}
+		if n != rows {
+			b.Fatalf("Read %d rows", n)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package native
+
+import (
+	"container/list"
+	"github.com/ziutek/mymysql/mysql"
+)
+
+// Default maximum number of statements in the cache used by PrepareCached
+const DefaultStmtCacheSize = 64
+
+type cachedStmt struct {
+	sql  string // SQL passed to PrepareCached
+	stmt *Stmt
+}
+
+type stmtCache struct {
+	size  int
+	lru   list.List // Front is the most recently used statement
+	m     map[string]*list.Element
+	stats mysql.StmtCacheStats
+}
+
+// Removes all statements from the cache (without deleting them) and marks
+// them as evicted. It is used when the server has already closed them.
+func (c *stmtCache) clear() {
+	for el := c.lru.Front(); el != nil; el = el.Next() {
+		if stmt := el.Value.(*cachedStmt).stmt; stmt.my != nil {
+			stmt.evicted = true
+		}
+	}
+	c.lru.Init()
+	c.m = nil
+}
+
+// Removes the least recently used statement from the cache and closes it on
+// the server side. The statement can be still held by the code that obtained
+// it from PrepareCached, so its handler isn't invalidated but marked as
+// evicted.
+func (my *Conn) evictStmt() error {
+	c := &my.stmt_cache
+	cs := c.lru.Remove(c.lru.Back()).(*cachedStmt)
+	delete(c.m, cs.sql)
+	c.stats.Evictions++
+	if cs.stmt.my == nil {
+		// Deleted before
+		return nil
+	}
+	cs.stmt.evicted = true
+	if my.net_conn == nil {
+		// There is nothing to close on the server side
+		delete(my.stmt_map, cs.stmt.id)
+		return nil
+	}
+	return cs.stmt.close()
+}
+
+// Returns prepared statement for sql from the cache or prepares it and adds
+// it to the cache. If the cache is full the least recently used statement is
+// evicted: it is closed on the server side and its methods that use the
+// server (Run, Exec..., Reset, SendLongData) return STMT_EVICTED_ERROR, so
+// call PrepareCached again instead of retaining the statement. If the
+// server refuses to prepare next statement because of
+// max_prepared_stmt_count limit, least recently used statements are evicted
+// until prepare succeeds or the cache is empty.
+//
+// Don't call Delete for cached statements. Cached statements are reprepared
+// by Reconnect as other statements. If caching is disabled (see
+// SetStmtCacheSize) PrepareCached works like Prepare: returned statements
+// aren't cached, so the caller should Delete them.
+func (my *Conn) PrepareCached(sql string) (mysql.Stmt, error) {
+	if my.net_conn == nil {
+		return nil, NOT_CONN_ERROR
+	}
+	if my.unreaded_reply {
+		return nil, UNREADED_REPLY_ERROR
+	}
+	c := &my.stmt_cache
+	if el, ok := c.m[sql]; ok {
+		if stmt := el.Value.(*cachedStmt).stmt; stmt.my != nil {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			return stmt, nil
+		}
+		// Statement was deleted by user
+		c.lru.Remove(el)
+		delete(c.m, sql)
+	}
+	c.stats.Misses++
+	if c.size <= 0 {
+		return my.Prepare(sql)
+	}
+	for c.lru.Len() >= c.size {
+		if err := my.evictStmt(); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		stmt, err := my.Prepare(sql)
+		if err == nil {
+			if c.m == nil {
+				c.m = make(map[string]*list.Element)
+			}
+			c.m[sql] = c.lru.PushFront(&cachedStmt{sql, stmt.(*Stmt)})
+			return stmt, nil
+		}
+		e, ok := err.(*mysql.Error)
+		if !ok || e.Code != mysql.ER_MAX_PREPARED_STMT_COUNT_REACHED ||
+			c.lru.Len() == 0 {
+			return nil, err
+		}
+		// Server limit reached, free it by deleting cached statements
+		if err = my.evictStmt(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Sets maximum number of statements in the cache used by PrepareCached
+// (default DefaultStmtCacheSize). If the cache contains more statements, least
+// recently used ones are deleted. If size <= 0 caching is disabled and
+// PrepareCached works like Prepare.
+func (my *Conn) SetStmtCacheSize(size int) error {
+	if my.unreaded_reply {
+		return UNREADED_REPLY_ERROR
+	}
+	c := &my.stmt_cache
+	c.size = size
+	for c.lru.Len() > 0 && c.lru.Len() > size {
+		if err := my.evictStmt(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns statistics of the cache used by PrepareCached.
+func (my *Conn) StmtCacheStats() mysql.StmtCacheStats {
+	s := my.stmt_cache.stats
+	s.Len = my.stmt_cache.lru.Len()
+	return s
+}
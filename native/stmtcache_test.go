@@ -0,0 +1,105 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"net"
+	"testing"
+)
+
+func TestStmtCacheEvict(t *testing.T) {
+	nc, peer := net.Pipe()
+	defer nc.Close()
+	defer peer.Close()
+
+	var out bytes.Buffer
+	my := &Conn{
+		net_conn:    nc,
+		wr:          bufio.NewWriter(&out),
+		stmt_map:    make(map[uint32]*Stmt),
+		max_allowed: 1 << 20,
+	}
+	held := &Stmt{my: my, id: 7, sql: "select 1"}
+	my.stmt_map[held.id] = held
+	c := &my.stmt_cache
+	c.size = 1
+	c.m = map[string]*list.Element{
+		held.sql: c.lru.PushFront(&cachedStmt{held.sql, held}),
+	}
+
+	// Evicts the statement that is still held
+	if err := my.SetStmtCacheSize(0); err != nil {
+		t.Fatal(err)
+	}
+	exp := []byte{5, 0, 0, 0, _COM_STMT_CLOSE, 7, 0, 0, 0}
+	if !bytes.Equal(out.Bytes(), exp) {
+		t.Fatalf("Bad COM_STMT_CLOSE: %v", out.Bytes())
+	}
+	if _, ok := my.stmt_map[held.id]; ok || c.lru.Len() != 0 {
+		t.Fatal("Statement not removed")
+	}
+
+	// Held statement reports an error and nothing is sent
+	out.Reset()
+	if _, err := held.Run(); err != STMT_EVICTED_ERROR {
+		t.Fatal("Run: expected STMT_EVICTED_ERROR, got:", err)
+	}
+	if _, _, err := held.Exec(); err != STMT_EVICTED_ERROR {
+		t.Fatal("Exec: expected STMT_EVICTED_ERROR, got:", err)
+	}
+	if err := held.Reset(); err != STMT_EVICTED_ERROR {
+		t.Fatal("Reset: expected STMT_EVICTED_ERROR, got:", err)
+	}
+	if err := held.SendLongData(0, "a", 100); err != STMT_EVICTED_ERROR {
+		t.Fatal("SendLongData: expected STMT_EVICTED_ERROR, got:", err)
+	}
+	p := my.Pipeline()
+	p.Exec(held)
+	results, err := p.Run()
+	if err != nil || results[0].Err != STMT_EVICTED_ERROR {
+		t.Fatal("Pipeline: expected STMT_EVICTED_ERROR, got:", err, results)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("Data sent for evicted statement: %v", out.Bytes())
+	}
+	if err := held.Delete(); err != nil || held.my != nil || out.Len() != 0 {
+		t.Fatal("Delete of evicted statement:", err)
+	}
+}
+
+func TestStmtCacheClear(t *testing.T) {
+	nc, peer := net.Pipe()
+	defer nc.Close()
+	defer peer.Close()
+
+	var out bytes.Buffer
+	my := &Conn{
+		net_conn: nc,
+		rd:       bufio.NewReader(bytes.NewReader(response(okPkt(0)))),
+		wr:       bufio.NewWriter(&out),
+		stmt_map: make(map[uint32]*Stmt),
+	}
+	my.stmt_cache.size = 2
+	held := &Stmt{my: my, id: 7, sql: "select 1"}
+	my.stmt_map[held.id] = held
+	c := &my.stmt_cache
+	c.m = map[string]*list.Element{
+		held.sql: c.lru.PushFront(&cachedStmt{held.sql, held}),
+	}
+
+	// Server deallocates all statements
+	if err := my.resetConn(); err != nil {
+		t.Fatal(err)
+	}
+	if c.lru.Len() != 0 || len(my.stmt_map) != 0 {
+		t.Fatal("Cache not cleared")
+	}
+	out.Reset()
+	if _, err := held.Run(); err != STMT_EVICTED_ERROR {
+		t.Fatal("Run: expected STMT_EVICTED_ERROR, got:", err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("Data sent for evicted statement: %v", out.Bytes())
+	}
+}
@@ -0,0 +1,200 @@
+package native
+
+import (
+	"github.com/ziutek/mymysql/mysql"
+	"io"
+	"log"
+)
+
+// Reader of one column value in the row stream
+type colReader struct {
+	rs *rowStream
+	n  int64 // Number of unread bytes
+}
+
+func (cr *colReader) Read(buf []byte) (int, error) {
+	if cr.rs.cur != cr {
+		return 0, STREAM_READER_ERROR
+	}
+	if cr.n == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(buf)) > cr.n {
+		buf = buf[:cr.n]
+	}
+	n, err := cr.rs.pr.Read(buf)
+	cr.n -= int64(n)
+	if err == io.EOF {
+		// End of packets before end of value
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Row that column values are read directly from the network connection.
+type rowStream struct {
+	res         *Result
+	pr          *pktReader
+	null_bitmap []byte // nil for text protocol
+	col         int    // Number of the next column
+	cur         *colReader
+	closed      bool
+}
+
+// Discards unread data of the current column reader
+func (rs *rowStream) discard() {
+	if rs.cur == nil {
+		return
+	}
+	if _, err := io.CopyN(io.Discard, rs.cur, rs.cur.n); err != nil {
+		panic(err)
+	}
+	rs.cur = nil
+}
+
+// Prepares reading of the next column. Returns its field and true if its
+// value is NULL.
+func (rs *rowStream) next() (field *mysql.Field, null bool) {
+	if rs.closed {
+		panic(STREAM_CLOSED_ERROR)
+	}
+	rs.discard()
+	if rs.col == rs.res.field_count {
+		panic(io.EOF)
+	}
+	field = rs.res.fields[rs.col]
+	if rs.null_bitmap != nil {
+		null = isBinNull(rs.null_bitmap, rs.col)
+	}
+	rs.col++
+	return
+}
+
+func (rs *rowStream) Column() int {
+	return rs.col
+}
+
+func (rs *rowStream) Value() (val interface{}, err error) {
+	defer catchError(&err)
+
+	field, null := rs.next()
+	switch {
+	case null:
+		return nil, nil
+	case rs.null_bitmap != nil:
+		return readBinValue(rs.pr, field), nil
+	}
+	bin, null := readNullBin(rs.pr)
+	switch {
+	case null:
+		return nil, nil
+	case rs.res.my.typed_text:
+		return textValue(bin, field), nil
+	}
+	return bin, nil
+}
+
+func (rs *rowStream) Reader() (rd io.Reader, length int64, err error) {
+	defer catchError(&err)
+
+	field, null := rs.next()
+	if null {
+		return nil, 0, nil
+	}
+	if rs.null_bitmap != nil && !isLenEncType(field.Type) {
+		// Value wasn't read, so it can be still read using Value method
+		rs.col--
+		return nil, 0, STREAM_TYPE_ERROR
+	}
+	n, null := readNullLCB(rs.pr)
+	if null {
+		return nil, 0, nil
+	}
+	rs.cur = &colReader{rs: rs, n: int64(n)}
+	return rs.cur, int64(n), nil
+}
+
+func (rs *rowStream) Close() (err error) {
+	if rs.closed {
+		return nil
+	}
+	defer catchError(&err)
+
+	for rs.col < rs.res.field_count {
+		field, null := rs.next()
+		switch {
+		case null:
+		case rs.null_bitmap != nil && !isLenEncType(field.Type):
+			readBinValue(rs.pr, field)
+		default:
+			if n, null := readNullLCB(rs.pr); !null {
+				rs.cur = &colReader{rs: rs, n: int64(n)}
+			}
+		}
+	}
+	rs.discard()
+	rs.closed = true
+	if rs.null_bitmap == nil {
+		rs.pr.checkEof()
+	}
+	return
+}
+
+func (res *Result) getStream() (rs *rowStream, err error) {
+	defer catchError(&err)
+
+	my := res.my
+	pr := my.newPktReader()
+	pkt0 := pr.readByte()
+	switch {
+	case pkt0 == 255:
+		my.getErrorPacket(pr)
+	case pkt0 == 254 && pr.remain < 8:
+		// EOF packet (row can start with 254 only if it is long)
+		res.warning_count, res.status = my.getEofPacket(pr)
+		my.status = res.status
+		return nil, io.EOF
+	}
+	rs = &rowStream{res: res, pr: pr}
+	if res.binary {
+		if my.Debug {
+			log.Printf("[%2d ->] Binary row data packet (stream)", my.seq-1)
+		}
+		if pkt0 != 0 {
+			panic(UNK_RESULT_PKT_ERROR)
+		}
+		rs.null_bitmap = make([]byte, (res.field_count+7+2)>>3)
+		readFull(pr, rs.null_bitmap)
+	} else {
+		if my.Debug {
+			log.Printf("[%2d ->] Text row data packet (stream)", my.seq-1)
+		}
+		pr.unreadByte()
+	}
+	res.stream = rs
+	return
+}
+
+// Like ScanRow but doesn't read the row into memory. Column values can be
+// read one by one using returned mysql.RowStream, directly from the network
+// connection. Large values can be read using io.Reader, so memory usage is
+// bounded regardless of the value size. The stream must be closed before next
+// ScanRow or ScanStream call. Returns io.EOF if there are no more rows.
+func (res *Result) ScanStream() (mysql.RowStream, error) {
+	if err := res.checkScan(); err != nil {
+		return nil, err
+	}
+	if res.StatusOnly() {
+		// There is no fields in result (OK result)
+		res.eor_returned = true
+		return nil, io.EOF
+	}
+	rs, err := res.getStream()
+	if err == io.EOF {
+		return nil, res.endOfRows()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
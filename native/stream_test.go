@@ -0,0 +1,143 @@
+package native
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/ziutek/mymysql/mysql"
+	"io"
+	"testing"
+)
+
+// Returns packet stream that contains payloads (long payloads are split into
+// many packets).
+func pktStream(payloads ...[]byte) []byte {
+	return pktStreamSeq(0, payloads...)
+}
+
+// Like pktStream but the first packet has sequence number seq.
+func pktStreamSeq(seq byte, payloads ...[]byte) []byte {
+	var buf bytes.Buffer
+	for _, p := range payloads {
+		pw := &pktWriter{wr: bufio.NewWriter(&buf), seq: &seq, to_write: len(p)}
+		write(pw, p)
+	}
+	return buf.Bytes()
+}
+
+// Returns result that reads rows from payloads
+func testResult(binary bool, fields []*mysql.Field, payloads ...[]byte) *Result {
+	my := &Conn{rd: bufio.NewReader(bytes.NewReader(pktStream(payloads...)))}
+	my.unreaded_reply = true
+	return &Result{
+		my:          my,
+		binary:      binary,
+		field_count: len(fields),
+		fields:      fields,
+	}
+}
+
+var eofPkt = []byte{254, 0, 0, 0, 0}
+
+func TestScanStreamText(t *testing.T) {
+	big := bytes.Repeat([]byte("0123456789abcdef"), 0x110000) // > 16 MB
+	var row1 bytes.Buffer
+	writeBin(&row1, big)
+	writeStr(&row1, "1")
+	row2 := []byte{251, 1, '2'} // NULL, "2"
+	res := testResult(
+		false,
+		[]*mysql.Field{
+			{Name: "data", Type: MYSQL_TYPE_BLOB},
+			{Name: "id", Type: MYSQL_TYPE_LONG},
+		},
+		row1.Bytes(), row2, eofPkt,
+	)
+
+	rs, err := res.ScanStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd, n, err := rs.Reader()
+	if err != nil || n != int64(len(big)) {
+		t.Fatalf("Reader: n=%d err=%v", n, err)
+	}
+	buf := make([]byte, 10)
+	if _, err = io.ReadFull(rd, buf); err != nil ||
+		!bytes.Equal(buf, big[:10]) {
+		t.Fatalf("Bad data: %q %v", buf, err)
+	}
+	if _, err = res.ScanStream(); err != STREAM_NOT_CLOSED_ERROR {
+		t.Fatal("Expected STREAM_NOT_CLOSED_ERROR, got:", err)
+	}
+	// Rest of the column is discarded
+	if v, err := rs.Value(); err != nil || string(v.([]byte)) != "1" {
+		t.Fatalf("Value: %v %v", v, err)
+	}
+	if _, err = rd.Read(buf); err != STREAM_READER_ERROR {
+		t.Fatal("Expected STREAM_READER_ERROR, got:", err)
+	}
+	if _, err = rs.Value(); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+	if err = rs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close discards unread columns
+	rs, err = res.ScanStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rd, _, err = rs.Reader(); rd != nil || err != nil {
+		t.Fatal("NULL: unexpected reader or error:", err)
+	}
+	if err = rs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = res.ScanStream(); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+	if res.my.unreaded_reply {
+		t.Fatal("unreaded_reply not cleared")
+	}
+}
+
+func TestScanStreamBinary(t *testing.T) {
+	// id=1, data="abc", x=NULL
+	row := []byte{0, 1 << 4, 1, 0, 0, 0, 3, 'a', 'b', 'c'}
+	res := testResult(
+		true,
+		[]*mysql.Field{
+			{Name: "id", Type: MYSQL_TYPE_LONG},
+			{Name: "data", Type: MYSQL_TYPE_BLOB},
+			{Name: "x", Type: MYSQL_TYPE_LONG},
+		},
+		row, eofPkt,
+	)
+	rs, err := res.ScanStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = rs.Reader(); err != STREAM_TYPE_ERROR {
+		t.Fatal("Expected STREAM_TYPE_ERROR, got:", err)
+	}
+	if v, err := rs.Value(); err != nil || v != int32(1) {
+		t.Fatalf("Value: %v %v", v, err)
+	}
+	rd, n, err := rs.Reader()
+	if err != nil || n != 3 {
+		t.Fatalf("Reader: n=%d err=%v", n, err)
+	}
+	if data, err := io.ReadAll(rd); err != nil || string(data) != "abc" {
+		t.Fatalf("Bad data: %q %v", data, err)
+	}
+	if v, err := rs.Value(); err != nil || v != nil {
+		t.Fatalf("NULL value: %v %v", v, err)
+	}
+	if err = rs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = res.ScanStream(); err != io.EOF {
+		t.Fatal("Expected io.EOF, got:", err)
+	}
+}
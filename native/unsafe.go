@@ -12,6 +12,12 @@ type paramValue struct {
 	addr   unsafe.Pointer
 	raw    bool
 	length int // >=0 - length of value, <0 - unknown length
+
+	// Converts driver.Valuer or registered type before every execution
+	conv func() (interface{}, error)
+
+	// Returns binded io.Reader (sent using COM_STMT_SEND_LONG_DATA)
+	reader func() io.Reader
 }
 
 func (pv *paramValue) SetAddr(addr uintptr) {
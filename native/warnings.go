@@ -0,0 +1,69 @@
+package native
+
+import (
+	"github.com/ziutek/mymysql/mysql"
+)
+
+// Enables or disables automatic warnings retrieval. If enabled, after the
+// whole reply to the command was read and its warning count is greater than
+// zero, SHOW WARNINGS query is sent to the server and its result is available
+// through Result.Warnings method.
+func (my *Conn) SetAutoWarnings(on bool) {
+	my.auto_warn = on
+}
+
+// Causes that warnings with specified codes are returned as errors (of type
+// *mysql.Error) by the method that completes reading the reply (Start, Run,
+// ScanRow, NextResult...). Enables automatic warnings retrieval. Call it
+// without arguments to disable this mode (automatic warnings retrieval stays
+// enabled). See also mysql.TruncationWarnings.
+func (my *Conn) SetStrictWarnings(codes ...uint16) {
+	if len(codes) == 0 {
+		my.strict_warn = nil
+		return
+	}
+	my.strict_warn = make(map[uint16]bool, len(codes))
+	for _, c := range codes {
+		my.strict_warn[c] = true
+	}
+	my.auto_warn = true
+}
+
+// Called when the whole reply to the command was read.
+func (my *Conn) replyEnd(res *Result) {
+	if !my.auto_warn || res.warning_count == 0 {
+		return
+	}
+	res.warnings = my.getWarnings()
+	for _, w := range res.warnings {
+		if my.strict_warn[w.Code] {
+			panic(&mysql.Error{Code: w.Code, Msg: []byte(w.Message)})
+		}
+	}
+}
+
+func (my *Conn) getWarnings() (warns []mysql.Warning) {
+	my.sendCmd(_COM_QUERY, "SHOW WARNINGS")
+	res := my.getResult(nil, nil)
+	if res == nil {
+		panic(BAD_RESULT_ERROR)
+	}
+	if res.StatusOnly() {
+		return
+	}
+	// Don't overwrite the last row of the result that caused warnings
+	row_buf := my.row_buf
+	my.row_buf = nil
+	defer func() {
+		my.row_buf = row_buf
+	}()
+	row := res.MakeRow()
+	for my.getResult(res, row) == nil {
+		warns = append(warns, mysql.Warning{
+			Level:   row.Str(0),
+			Code:    uint16(row.Uint(1)),
+			Message: row.Str(2),
+		})
+	}
+	return
+}
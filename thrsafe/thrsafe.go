@@ -10,8 +10,9 @@ package thrsafe
 
 import (
 	"github.com/ziutek/mymysql/mysql"
-	_ "github.com/ziutek/mymysql/native"
+	"github.com/ziutek/mymysql/native"
 	"io"
+	"iter"
 	"sync"
 	"time"
 )
@@ -50,6 +51,11 @@ type Transaction struct {
 	conn *Conn
 }
 
+type Pipeline struct {
+	mysql.Pipeline
+	conn *Conn
+}
+
 func New(proto, laddr, raddr, user, passwd string, db ...string) mysql.Conn {
 	return &Conn{
 		Conn:  orgNew(proto, laddr, raddr, user, passwd, db...),
@@ -120,6 +126,20 @@ func (c *Conn) Use(dbname string) error {
 	return c.Conn.Use(dbname)
 }
 
+func (c *Conn) ChangeUser(user, passwd, db string) error {
+	//log.Println("ChangeUser")
+	c.lock()
+	defer c.unlock()
+	return c.Conn.ChangeUser(user, passwd, db)
+}
+
+func (c *Conn) ResetSession() error {
+	//log.Println("ResetSession")
+	c.lock()
+	defer c.unlock()
+	return c.Conn.ResetSession()
+}
+
 func (c *Conn) Start(sql string, params ...interface{}) (mysql.Result, error) {
 	//log.Println("Start")
 	c.lock()
@@ -137,10 +157,30 @@ func (c *Conn) Start(sql string, params ...interface{}) (mysql.Result, error) {
 
 func (res *Result) ScanRow(row mysql.Row) error {
 	//log.Println("ScanRow")
-	err := res.Result.ScanRow(row)
-	if err == nil {
-		// There are more rows to read
-		return nil
+	return res.scanned(res.Result.ScanRow(row))
+}
+
+func (res *Result) ScanStream() (mysql.RowStream, error) {
+	rs, err := res.Result.ScanStream()
+	return rs, res.scanned(err)
+}
+
+func (res *Result) ScanInto(dest ...interface{}) error {
+	return res.scanned(res.Result.ScanInto(dest...))
+}
+
+// Unlocks the connection if err returned by ScanRow/ScanStream/ScanInto
+// means that the reply was read.
+func (res *Result) scanned(err error) error {
+	if err == nil || err == mysql.READ_AFTER_EOR_ERROR ||
+		err == native.ROW_LENGTH_ERROR || err == native.STREAM_NOT_CLOSED_ERROR {
+		// There are more rows to read, the result was read before (and
+		// the connection was unlocked then) or nothing was read
+		return err
+	}
+	if _, ok := err.(*native.ScanIntoError); ok {
+		// Row wasn't read or was read completely
+		return err
 	}
 	if err != io.EOF || !res.StatusOnly() && !res.MoreResults() {
 		// Error or no more rows in not empty result set and no more resutls.
@@ -187,6 +227,28 @@ func (c *Conn) Prepare(sql string) (mysql.Stmt, error) {
 	return &Stmt{Stmt: stmt, conn: c}, nil
 }
 
+func (c *Conn) PrepareCached(sql string) (mysql.Stmt, error) {
+	c.lock()
+	defer c.unlock()
+	stmt, err := c.Conn.PrepareCached(sql)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{Stmt: stmt, conn: c}, nil
+}
+
+func (c *Conn) SetStmtCacheSize(size int) error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.SetStmtCacheSize(size)
+}
+
+func (c *Conn) StmtCacheStats() mysql.StmtCacheStats {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.StmtCacheStats()
+}
+
 func (stmt *Stmt) Run(params ...interface{}) (mysql.Result, error) {
 	//log.Println("Run")
 	stmt.conn.lock()
@@ -223,6 +285,67 @@ func (stmt *Stmt) SendLongData(pnum int, data interface{}, pkt_size int) error {
 	return stmt.Stmt.SendLongData(pnum, data, pkt_size)
 }
 
+func (c *Conn) Pipeline() mysql.Pipeline {
+	return &Pipeline{Pipeline: c.Conn.Pipeline(), conn: c}
+}
+
+func (p *Pipeline) Exec(st mysql.Stmt, params ...interface{}) {
+	if s, ok := st.(*Stmt); ok {
+		st = s.Stmt
+	}
+	p.Pipeline.Exec(st, params...)
+}
+
+// Responses of all commands are read by Run, so the connection is locked only
+// during this call.
+func (p *Pipeline) Run() ([]mysql.PipelineResult, error) {
+	p.conn.lock()
+	defer p.conn.unlock()
+	return p.Pipeline.Run()
+}
+
+func (c *Conn) SetMultiStatements(on bool) error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.SetMultiStatements(on)
+}
+
+func (c *Conn) Statistics() (mysql.Statistics, error) {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.Statistics()
+}
+
+func (c *Conn) ProcessList() ([]mysql.Row, mysql.Result, error) {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.ProcessList()
+}
+
+func (c *Conn) Kill(thr_id uint32) error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.Kill(thr_id)
+}
+
+func (c *Conn) Refresh(flags byte) error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.Refresh(flags)
+}
+
+func (c *Conn) Shutdown() error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.Shutdown()
+}
+
+func (c *Conn) DumpDebugInfo() error {
+	c.lock()
+	defer c.unlock()
+	return c.Conn.DumpDebugInfo()
+}
+
 // See mysql.Query
 func (c *Conn) Query(sql string, params ...interface{}) ([]mysql.Row, mysql.Result, error) {
 	return mysql.Query(c, sql, params...)
@@ -248,11 +371,26 @@ func (stmt *Stmt) ExecFirst(params ...interface{}) (mysql.Row, mysql.Result, err
 	return mysql.ExecFirst(stmt, params...)
 }
 
+// See mysql.ExecCall
+func (stmt *Stmt) ExecCall(params ...interface{}) (*mysql.CallResult, error) {
+	return mysql.ExecCall(stmt, params...)
+}
+
 // See mysql.ExecLast
 func (stmt *Stmt) ExecLast(params ...interface{}) (mysql.Row, mysql.Result, error) {
 	return mysql.ExecLast(stmt, params...)
 }
 
+// See mysql.All
+func (res *Result) All() iter.Seq2[mysql.Row, error] {
+	return mysql.All(res)
+}
+
+// See mysql.Results
+func (res *Result) Results() iter.Seq2[mysql.Result, error] {
+	return mysql.Results(res)
+}
+
 // See mysql.End
 func (res *Result) End() error {
 	return mysql.End(res)
@@ -28,6 +28,7 @@ func checkErr(t *testing.T, err error) {
 func connect(t *testing.T) mysql.Conn {
 	db := New(proto, "", daddr, user, passwd, dbname)
 	db.(*Conn).Conn.(*native.Conn).Debug = debug
+	checkErr(t, db.SetMultiStatements(true))
 	checkErr(t, db.Connect())
 	return db
 }